@@ -0,0 +1,220 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Sample returns up to n randomly selected documents using $sample.
+//
+// Asking for more documents than the collection holds is not an error;
+// every document is returned in random order instead.
+func (m *mongoModel[T, C]) Sample(ctx context.Context, n int64) ([]T, error) {
+	defer m.track("Sample")()
+
+	if n <= 0 {
+		return nil, fmt.Errorf("sample size must be positive, got %d", n)
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("Sample"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$sample", Value: bson.D{{Key: "size", Value: n}}}},
+	}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute sample: %w", err)
+	}
+
+	return decodeCursor[T](ctx, cursor)
+}
+
+// AggregateInto executes an aggregation pipeline and decodes all results
+// into dest, reusing the caller's slice instead of allocating a new one.
+//
+// Like Aggregate, it returns mongo.ErrNoDocuments when the pipeline
+// produces no results.
+func (m *mongoModel[T, C]) AggregateInto(
+	ctx context.Context,
+	pipeline mongo.Pipeline,
+	dest *[]C,
+) error {
+	defer m.track("AggregateInto")()
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("AggregateInto"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+
+	if err := cursor.All(ctx, dest); err != nil {
+		return fmt.Errorf("failed to decode aggregation result: %w", err)
+	}
+
+	if len(*dest) == 0 {
+		return mongo.ErrNoDocuments
+	}
+
+	return nil
+}
+
+// AggregateTo executes a pipeline whose last stage writes its results to
+// another collection ($merge or $out) without decoding any results.
+//
+// ErrInvalidPipeline is returned when the pipeline does not end with an
+// output stage, since running it would silently discard the results.
+func (m *mongoModel[T, C]) AggregateTo(ctx context.Context, pipeline mongo.Pipeline) error {
+	defer m.track("AggregateTo")()
+
+	if len(pipeline) == 0 {
+		return fmt.Errorf("%w: empty pipeline", ErrInvalidPipeline)
+	}
+	last := pipeline[len(pipeline)-1]
+	if len(last) != 1 || (last[0].Key != "$merge" && last[0].Key != "$out") {
+		return fmt.Errorf("%w: last stage must be $merge or $out", ErrInvalidPipeline)
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("AggregateTo"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	return cursor.Close(ctx)
+}
+
+// DistinctGroups returns the distinct combinations of the given fields
+// among the documents matching filter, one map per combination.
+//
+// Each map is keyed by field name, sorted by the fields in order.
+// Documents missing a field group under a missing key. Dotted paths
+// are allowed, and their dots are replaced by underscores in the
+// result keys since keys of a group may not contain dots.
+func (m *mongoModel[T, C]) DistinctGroups(ctx context.Context, fields []string, filter any) ([]bson.M, error) {
+	defer m.track("DistinctGroups")()
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: no fields to group by", ErrInvalidFieldPath)
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	key := bson.D{}
+	sort := bson.D{}
+	for _, field := range fields {
+		if err := validateFieldPath(field); err != nil {
+			return nil, err
+		}
+		name := strings.ReplaceAll(field, ".", "_")
+		key = append(key, bson.E{Key: name, Value: "$" + field})
+		sort = append(sort, bson.E{Key: name, Value: 1})
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("DistinctGroups"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, Pipeline().
+		Match(filter).
+		Stage(bson.D{{Key: "$group", Value: bson.D{{Key: "_id", Value: key}}}}).
+		Stage(bson.D{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$_id"}}}}).
+		Stage(bson.D{{Key: "$sort", Value: sort}}).
+		Build(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+
+	return decodeCursor[bson.M](ctx, cursor)
+}
+
+// dateBucketFormats maps each CountByDate granularity to the
+// $dateToString format of its bucket keys.
+var dateBucketFormats = map[string]string{
+	"year":   "%Y",
+	"month":  "%Y-%m",
+	"week":   "%Y-%m-%d",
+	"day":    "%Y-%m-%d",
+	"hour":   "%Y-%m-%dT%H",
+	"minute": "%Y-%m-%dT%H:%M",
+}
+
+// CountByDate counts the documents matching filter per date bucket of
+// dateField, e.g. per day for dashboards.
+//
+// granularity is one of "year", "month", "week", "day", "hour" or
+// "minute". Buckets are computed in UTC and keyed by their start, e.g.
+// "2024-03-01" for a day or "2024-03-01T14" for an hour; weeks start on
+// Sunday and are keyed by that day. Week buckets use $dateTrunc and
+// require MongoDB 5.0 or later. Documents without the field are skipped.
+func (m *mongoModel[T, C]) CountByDate(
+	ctx context.Context,
+	dateField string,
+	granularity string,
+	filter any,
+) (map[string]int64, error) {
+	defer m.track("CountByDate")()
+
+	format, ok := dateBucketFormats[granularity]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidGranularity, granularity)
+	}
+	if err := validateFieldPath(dateField); err != nil {
+		return nil, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	var date any = "$" + dateField
+	if granularity == "week" {
+		date = bson.D{{Key: "$dateTrunc", Value: bson.D{
+			{Key: "date", Value: date},
+			{Key: "unit", Value: "week"},
+		}}}
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("CountByDate"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, Pipeline().
+		Match(filter).
+		Match(bson.D{{Key: dateField, Value: bson.D{{Key: "$type", Value: "date"}}}}).
+		Stage(bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: bson.D{{Key: "$dateToString", Value: bson.D{
+				{Key: "format", Value: format},
+				{Key: "date", Value: date},
+			}}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}).
+		Build(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+
+	buckets, err := decodeCursor[struct {
+		Key   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(buckets))
+	for _, b := range buckets {
+		counts[b.Key] = b.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,224 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestSample(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("sample_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "sample_users")
+	for i := range 100 {
+		err := model.Create(ctx, testUser{
+			ID:   fmt.Sprintf("%d", i),
+			Name: fmt.Sprintf("user-%d", i),
+			Age:  i,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("distinct documents", func(t *testing.T) {
+		users, err := model.Sample(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 10 {
+			t.Fatalf("expected 10, got %d", len(users))
+		}
+
+		seen := make(map[string]bool)
+		for _, u := range users {
+			if seen[u.ID] {
+				t.Fatalf("duplicate document %s", u.ID)
+			}
+			seen[u.ID] = true
+		}
+	})
+
+	t.Run("larger than collection", func(t *testing.T) {
+		users, err := model.Sample(ctx, 500)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 100 {
+			t.Fatalf("expected 100, got %d", len(users))
+		}
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		if _, err := model.Sample(ctx, 0); err == nil {
+			t.Fatal("expected error for zero sample size")
+		}
+	})
+}
+
+func seedAggregateUsers(tb testing.TB, model DefaultModel[testUser, testEmployee], n int) {
+	tb.Helper()
+	ctx := context.Background()
+	for i := range n {
+		err := model.Create(ctx, testUser{
+			ID:       fmt.Sprintf("%04d", i),
+			Name:     fmt.Sprintf("user-%d", i),
+			Position: "Dev",
+		})
+		if err != nil {
+			tb.Fatal(err)
+		}
+	}
+}
+
+var projectEmployees = mongo.Pipeline{
+	{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	{{Key: "$project", Value: bson.D{
+		{Key: "first_name", Value: "$name"},
+		{Key: "position", Value: 1},
+	}}},
+}
+
+func TestAggregateInto(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("aggregate_into").Drop(ctx)
+
+	model := New[testUser, testEmployee](db, "aggregate_into")
+	seedAggregateUsers(t, model, 10)
+
+	expected, err := model.Aggregate(ctx, projectEmployees)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []testEmployee
+	if err := model.AggregateInto(ctx, projectEmployees, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+
+	empty := mongo.Pipeline{{{Key: "$match", Value: bson.D{{Key: "_id", Value: "missing"}}}}}
+	if err := model.AggregateInto(ctx, empty, &got); err != mongo.ErrNoDocuments {
+		t.Fatalf("expected ErrNoDocuments, got %v", err)
+	}
+}
+
+func BenchmarkAggregate(b *testing.B) {
+	ctx := context.Background()
+	db := testDatabase(b)
+	_ = db.Collection("aggregate_bench").Drop(ctx)
+
+	model := New[testUser, testEmployee](db, "aggregate_bench")
+	seedAggregateUsers(b, model, 1000)
+
+	b.Run("Aggregate", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := model.Aggregate(ctx, projectEmployees); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("AggregateInto", func(b *testing.B) {
+		var results []testEmployee
+		for b.Loop() {
+			if err := model.AggregateInto(ctx, projectEmployees, &results); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestDistinctGroups(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("distinct_groups").Drop(ctx)
+
+	model := New[testUser, testUser](db, "distinct_groups")
+	for i, u := range []testUser{
+		{Position: "Dev", Age: 30},
+		{Position: "Dev", Age: 30},
+		{Position: "Dev", Age: 35},
+		{Position: "QA", Age: 30},
+		{Position: "QA", Age: 30},
+	} {
+		u.ID = fmt.Sprintf("%d", i)
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groups, err := model.DistinctGroups(ctx, []string{"position", "age"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []bson.M{
+		{"position": "Dev", "age": int32(30)},
+		{"position": "Dev", "age": int32(35)},
+		{"position": "QA", "age": int32(30)},
+	}
+	if !reflect.DeepEqual(groups, expected) {
+		t.Fatalf("expected %v, got %v", expected, groups)
+	}
+
+	groups, err = model.DistinctGroups(ctx, []string{"position", "age"}, bson.D{{Key: "position", Value: "QA"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group, got %v", groups)
+	}
+}
+
+func TestCountByDate(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("date_events").Drop(ctx)
+
+	model := New[testEvent, testEvent](db, "date_events")
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{
+		day.Add(1 * time.Hour),
+		day.Add(5 * time.Hour),
+		day.Add(23 * time.Hour),
+		day.Add(25 * time.Hour),
+		day.Add(72 * time.Hour),
+	} {
+		if err := model.Create(ctx, testEvent{ID: fmt.Sprintf("%d", i), Kind: "login", CreatedAt: at}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := model.CountByDate(ctx, "created_at", "day", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]int64{"2024-03-01": 3, "2024-03-02": 1, "2024-03-04": 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("expected %v, got %v", expected, counts)
+	}
+
+	counts, err = model.CountByDate(ctx, "created_at", "month", map[string]any{"kind": "login"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(counts, map[string]int64{"2024-03": 5}) {
+		t.Fatalf("unexpected monthly counts %v", counts)
+	}
+
+	if _, err := model.CountByDate(ctx, "created_at", "fortnight", nil); !errors.Is(err, ErrInvalidGranularity) {
+		t.Fatalf("expected ErrInvalidGranularity, got %v", err)
+	}
+}
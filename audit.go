@@ -0,0 +1,30 @@
+package mongodb
+
+import "context"
+
+// AuditSink receives a record of a successful write operation.
+//
+// op is the name of the model method that performed the write, e.g.
+// "Create" or "UpdateMany". filter is the filter the write matched on
+// (nil for inserts) and payload is the inserted document or the update
+// (nil for deletes).
+type AuditSink func(ctx context.Context, op string, filter, payload any)
+
+// WithAuditSink invokes sink after every successful write of the model.
+//
+// The sink runs synchronously on the calling goroutine, after the write
+// has been acknowledged, so a slow sink delays the write's return.
+// Sinks that need to do expensive work should hand the record off,
+// e.g. to a buffered channel, and return.
+func WithAuditSink(sink AuditSink) ModelOption {
+	return func(c *modelConfig) {
+		c.auditSink = sink
+	}
+}
+
+// audit reports a successful write to the configured sink, if any.
+func (m *mongoModel[T, C]) audit(ctx context.Context, op string, filter, payload any) {
+	if m.config.auditSink != nil {
+		m.config.auditSink(ctx, op, filter, payload)
+	}
+}
@@ -0,0 +1,57 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestWithAuditSink(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("audit_users").Drop(ctx)
+
+	var (
+		mu  sync.Mutex
+		ops []string
+	)
+	sink := func(_ context.Context, op string, _, _ any) {
+		mu.Lock()
+		defer mu.Unlock()
+		ops = append(ops, op)
+	}
+
+	model := New[testUser, testUser](db, "audit_users", WithAuditSink(sink))
+
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Position: "Dev"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Create(ctx, testUser{ID: "2", Name: "Bob", Position: "Dev"}); err != nil {
+		t.Fatal(err)
+	}
+	filter := map[string]any{"_id": "1"}
+	if err := model.UpdateOne(ctx, filter, map[string]any{"$set": map[string]any{"age": 31}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.UpdateMany(ctx, map[string]any{}, map[string]any{"$set": map[string]any{"position": "QA"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.DeleteOne(ctx, filter); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.DeleteMany(ctx, map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed write must not be audited.
+	_ = model.Create(ctx, testUser{ID: "3"})
+	_ = model.Create(ctx, testUser{ID: "3"})
+
+	expected := []string{"Create", "Create", "UpdateOne", "UpdateMany", "DeleteOne", "DeleteMany", "Create"}
+	mu.Lock()
+	defer mu.Unlock()
+	if !reflect.DeepEqual(ops, expected) {
+		t.Fatalf("expected %v, got %v", expected, ops)
+	}
+}
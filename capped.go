@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// IsCapped reports whether the collection is capped, along with its
+// maximum size in bytes and maximum number of documents.
+//
+// The values are read from the listCollections options, so a collection
+// that does not exist yet is reported as not capped.
+func (m *mongoModel[T, C]) IsCapped(ctx context.Context) (bool, int64, int64, error) {
+	defer m.track("IsCapped")()
+
+	specs, err := m.collection.Database().ListCollectionSpecifications(
+		ctx,
+		bson.D{{Key: "name", Value: m.Name}},
+	)
+	if err != nil {
+		return false, 0, 0, err
+	}
+	if len(specs) == 0 || specs[0].Options == nil {
+		return false, 0, 0, nil
+	}
+
+	opts := specs[0].Options
+	capped, _ := opts.Lookup("capped").BooleanOK()
+	if !capped {
+		return false, 0, 0, nil
+	}
+	maxSize, _ := opts.Lookup("size").AsInt64OK()
+	maxDocs, _ := opts.Lookup("max").AsInt64OK()
+	return true, maxSize, maxDocs, nil
+}
+
+// tailRetryInterval is how long Tail waits before reopening a tailable
+// cursor on an empty capped collection.
+const tailRetryInterval = 100 * time.Millisecond
+
+// Append inserts v at the end of a capped collection used as a log.
+//
+// It is a Create documented for capped semantics: entries keep insertion
+// order, and once the collection is full the oldest entries are
+// overwritten. Capped collections do not allow deletes, and updates must
+// not grow a document.
+func (m *mongoModel[T, C]) Append(ctx context.Context, v T) error {
+	defer m.track("Append")()
+
+	_, err := m.insertOne(ctx, "Append", v)
+	return err
+}
+
+// Tail calls fn with every entry of a capped collection in insertion
+// order, then keeps following the collection with a tailable cursor,
+// calling fn as new entries are appended.
+//
+// Tail blocks until ctx is cancelled, which is a clean shutdown and
+// returns nil, or until fn or the cursor fails. While the collection is
+// empty Tail waits for the first entry. If the writers overwrite the
+// entry the cursor is positioned on, Tail cannot resume and fails.
+func (m *mongoModel[T, C]) Tail(ctx context.Context, fn func(T) error) error {
+	defer m.track("Tail")()
+
+	opts := options.Find().SetCursorType(options.TailableAwait)
+	if comment := m.queryComment("Tail"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	for {
+		cursor, err := m.collection.Find(ctx, bson.D{}, opts)
+		if err != nil {
+			return tailError(ctx, err)
+		}
+
+		delivered := false
+		for cursor.Next(ctx) {
+			var v T
+			if err := cursor.Decode(&v); err != nil {
+				_ = cursor.Close(context.WithoutCancel(ctx))
+				return err
+			}
+			if err := fn(v); err != nil {
+				_ = cursor.Close(context.WithoutCancel(ctx))
+				return err
+			}
+			delivered = true
+		}
+		err = cursor.Err()
+		_ = cursor.Close(context.WithoutCancel(ctx))
+		if err != nil {
+			return tailError(ctx, err)
+		}
+		if delivered {
+			return errors.New("tailable cursor lost its position in the capped collection")
+		}
+
+		// A tailable cursor on an empty collection is closed right away.
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tailRetryInterval):
+		}
+	}
+}
+
+// tailError reports err unless ctx has ended, in which case the error is
+// the cursor noticing the shutdown.
+func tailError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,120 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestIsCapped(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("capped_logs").Drop(ctx)
+	_ = db.Collection("plain_logs").Drop(ctx)
+
+	err := db.CreateCollection(
+		ctx,
+		"capped_logs",
+		options.CreateCollection().
+			SetCapped(true).
+			SetSizeInBytes(4096).
+			SetMaxDocuments(10),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("capped", func(t *testing.T) {
+		model := New[testUser, testUser](db, "capped_logs")
+		capped, maxSize, maxDocs, err := model.IsCapped(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !capped {
+			t.Fatal("expected capped collection")
+		}
+		if maxSize != 4096 {
+			t.Fatalf("expected size 4096, got %d", maxSize)
+		}
+		if maxDocs != 10 {
+			t.Fatalf("expected max 10, got %d", maxDocs)
+		}
+	})
+
+	t.Run("not capped", func(t *testing.T) {
+		model := New[testUser, testUser](db, "plain_logs")
+		if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+			t.Fatal(err)
+		}
+		capped, _, _, err := model.IsCapped(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if capped {
+			t.Fatal("expected non-capped collection")
+		}
+	})
+}
+
+func TestAppendTail(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("capped_tail").Drop(ctx)
+
+	err := db.CreateCollection(
+		ctx,
+		"capped_tail",
+		options.CreateCollection().SetCapped(true).SetSizeInBytes(4096),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	model := New[testUser, testUser](db, "capped_tail")
+
+	for _, id := range []string{"1", "2"} {
+		if err := model.Append(ctx, testUser{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tailCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	errDone := errors.New("done")
+	var got []string
+	tailed := make(chan error, 1)
+	go func() {
+		tailed <- model.Tail(tailCtx, func(u testUser) error {
+			got = append(got, u.ID)
+			if len(got) == 4 {
+				return errDone
+			}
+			return nil
+		})
+	}()
+
+	for _, id := range []string{"3", "4"} {
+		if err := model.Append(ctx, testUser{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := <-tailed; !errors.Is(err, errDone) {
+		t.Fatalf("expected errDone, got %v", err)
+	}
+	if fmt.Sprint(got) != "[1 2 3 4]" {
+		t.Fatalf("expected [1 2 3 4], got %v", got)
+	}
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := model.Tail(cancelled, func(testUser) error { return nil }); err != nil {
+			t.Fatalf("expected nil, got %v", err)
+		}
+	})
+}
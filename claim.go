@@ -0,0 +1,45 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Claim atomically picks a document matching filter, applies claimUpdate
+// to it and returns the claimed document as updated, or ErrNotFound when
+// none is available. This is the building block of job queues:
+//
+//	job, err := jobs.Claim(ctx,
+//		bson.D{{Key: "status", Value: "pending"}},
+//		bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: "processing"}}}},
+//		bson.D{{Key: "created_at", Value: 1}},
+//	)
+//
+// claimUpdate must change a field of filter, so a claimed document no
+// longer matches and each document is claimed by exactly one caller. An
+// optional sort picks which matching document is claimed first, e.g. the
+// oldest; only the first sort is used.
+func (m *mongoModel[T, C]) Claim(ctx context.Context, filter any, claimUpdate any, sort ...any) (T, error) {
+	defer m.track("Claim")()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if len(sort) > 0 && sort[0] != nil {
+		opts = opts.SetSort(sort[0])
+	}
+	if comment := m.queryComment("Claim"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	var result T
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	err := m.collection.FindOneAndUpdate(wctx, filter, claimUpdate, opts).Decode(&result)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return result, err
+	}
+	m.audit(ctx, "Claim", filter, claimUpdate)
+	return result, err
+}
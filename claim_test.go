@@ -0,0 +1,92 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testJob struct {
+	ID        string    `bson:"_id"`
+	Status    string    `bson:"status"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("claim_jobs").Drop(ctx)
+
+	model := New[testJob, testJob](db, "claim_jobs")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	const jobs = 20
+	for i := range jobs {
+		job := testJob{
+			ID:        fmt.Sprintf("%02d", i),
+			Status:    "pending",
+			CreatedAt: base.Add(time.Duration(jobs-i) * time.Minute),
+		}
+		if err := model.Create(ctx, job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pending := bson.D{{Key: "status", Value: "pending"}}
+	claim := bson.D{{Key: "$set", Value: bson.D{{Key: "status", Value: "processing"}}}}
+	oldestFirst := bson.D{{Key: "created_at", Value: 1}}
+
+	t.Run("oldest first", func(t *testing.T) {
+		job, err := model.Claim(ctx, pending, claim, oldestFirst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.ID != "19" {
+			t.Fatalf("expected oldest job 19, got %s", job.ID)
+		}
+		if job.Status != "processing" {
+			t.Fatalf("expected claimed status, got %s", job.Status)
+		}
+	})
+
+	t.Run("concurrent claimers", func(t *testing.T) {
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			claimed = make(map[string]int)
+		)
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for {
+					job, err := model.Claim(ctx, pending, claim, oldestFirst)
+					if errors.Is(err, ErrNotFound) {
+						return
+					}
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					mu.Lock()
+					claimed[job.ID]++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(claimed) != jobs-1 {
+			t.Fatalf("expected %d claimed jobs, got %d", jobs-1, len(claimed))
+		}
+		for id, n := range claimed {
+			if n != 1 {
+				t.Fatalf("job %s claimed %d times", id, n)
+			}
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestCollation(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("collation_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "collation_users")
+	for _, u := range []testUser{
+		{ID: "1", Email: "alice@test.com", Position: "Dev"},
+		{ID: "2", Email: "Alice@Test.com", Position: "Dev"},
+		{ID: "3", Email: "bob@test.com", Position: "Dev"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	caseInsensitive := &options.Collation{Locale: "en", Strength: 2}
+	alice := map[string]any{"email": "ALICE@TEST.COM"}
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		err := model.UpdateMany(
+			ctx,
+			alice,
+			map[string]any{"$set": map[string]any{"position": "Lead"}},
+			&options.UpdateManyOptions{Collation: caseInsensitive},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		leads, err := model.FindMany(ctx, map[string]any{"position": "Lead"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(leads) != 2 {
+			t.Fatalf("expected both case variants updated, got %+v", leads)
+		}
+	})
+
+	t.Run("DeleteMany", func(t *testing.T) {
+		err := model.DeleteMany(ctx, alice, &options.DeleteManyOptions{Collation: caseInsensitive})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		users, err := model.FindMany(ctx, map[string]any{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 1 || users[0].ID != "3" {
+			t.Fatalf("expected only bob to remain, got %+v", users)
+		}
+	})
+
+	t.Run("DeleteOne", func(t *testing.T) {
+		err := model.DeleteOne(
+			ctx,
+			map[string]any{"email": "BOB@TEST.COM"},
+			&options.DeleteOneOptions{Collation: caseInsensitive},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		users, err := model.FindMany(ctx, map[string]any{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 0 {
+			t.Fatalf("expected empty collection, got %+v", users)
+		}
+	})
+}
+
+func TestCreateCollatedIndex(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("collated_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "collated_users")
+	name, err := model.CreateCollatedIndex(
+		ctx,
+		bson.D{{Key: "email", Value: 1}},
+		&options.Collation{Locale: "en", Strength: 2},
+		true,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "email_1" {
+		t.Fatalf("expected email_1, got %s", name)
+	}
+
+	if err := model.Create(ctx, testUser{ID: "1", Email: "Alice@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+	err = model.Create(ctx, testUser{ID: "2", Email: "alice@test.com"})
+	if !mongo.IsDuplicateKeyError(err) {
+		t.Fatalf("expected duplicate key error, got %v", err)
+	}
+	if err := model.Create(ctx, testUser{ID: "3", Email: "bob@test.com"}); err != nil {
+		t.Fatal(err)
+	}
+}
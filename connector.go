@@ -1,8 +1,16 @@
 package mongodb
 
 import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
 )
 
 // Connector defines a generic interface for establishing a connection
@@ -29,34 +37,253 @@ type DatabaseConnector struct {
 	// If provided, it overrides the default options created from the URI.
 	ClientOptions *options.ClientOptions
 
+	// PoolMonitor receives connection pool events such as checkouts,
+	// checkins and timeouts. It is applied on top of ClientOptions.
+	PoolMonitor *event.PoolMonitor
+
+	// ClientTimeout is the client-side operation timeout (CSOT) applied
+	// to every operation. Zero leaves the driver default in place.
+	ClientTimeout time.Duration
+
+	// MaxStaleness bounds how far behind the primary a secondary may be
+	// to serve reads. Zero leaves staleness unbounded.
+	MaxStaleness time.Duration
+
+	// HedgedReads enables or disables hedged reads on sharded clusters.
+	// Nil leaves the server default in place.
+	HedgedReads *bool
+
+	// Keepalive enables or disables TCP keepalive probes on the client's
+	// connections. Nil leaves the driver dialer in place.
+	Keepalive *bool
+
+	// Dialer opens the client's connections instead of the driver dialer,
+	// e.g. through a proxy. Nil leaves the driver dialer in place.
+	Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// ServedReadHook is called after every successful read with the
+	// server that served it. It is chained after the monitors set on
+	// ClientOptions.
+	ServedReadHook func(ServedRead)
+
 	// Client holds the underlying MongoDB client instance created during Connect.
 	// It can be used to access client-level operations or to close the connection
 	// when it is no longer needed.
 	Client *mongo.Client
 }
 
+// ConnectorOption configures optional settings of a DatabaseConnector.
+type ConnectorOption func(*DatabaseConnector)
+
+// WithPoolMonitor registers a monitor for connection pool events.
+//
+// Pool events are the main tool for diagnosing pool exhaustion,
+// e.g. "too many connections" errors or checkout timeouts.
+func WithPoolMonitor(monitor *event.PoolMonitor) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.PoolMonitor = monitor
+	}
+}
+
+// WithClientTimeout sets a client-side operation timeout (CSOT) honored
+// by every operation executed through the client.
+//
+// The timeout bounds the whole operation, including server selection,
+// connection checkout and retries. When the operation context carries
+// its own deadline, the context deadline is used instead, so callers
+// can still shorten (or extend) individual operations.
+func WithClientTimeout(d time.Duration) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.ClientTimeout = d
+	}
+}
+
+// WithMaxStaleness bounds how stale a secondary may be to serve reads.
+//
+// Max staleness is not allowed with a primary read preference, so unless
+// the URI or ClientOptions pick another mode, reads switch to
+// secondaryPreferred. The server requires at least 90 seconds.
+func WithMaxStaleness(d time.Duration) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.MaxStaleness = d
+	}
+}
+
+// WithHedgedReads enables or disables hedged reads, where mongos sends
+// each read to two replica set members and returns the first response,
+// reducing tail latency on sharded clusters.
+//
+// Hedging is not allowed with a primary read preference, so unless the
+// URI or ClientOptions pick another mode, reads switch to
+// secondaryPreferred. Servers deprecate hedged reads starting with 8.0.
+func WithHedgedReads(enabled bool) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.HedgedReads = &enabled
+	}
+}
+
+// keepaliveInterval is the TCP keepalive period used by WithKeepalive(true),
+// well below the idle timeouts of common NAT gateways and firewalls.
+const keepaliveInterval = 15 * time.Second
+
+// WithKeepalive enables or disables TCP keepalive probes, which keep idle
+// pooled connections from being silently dropped by NATs and firewalls
+// and failing on their next use.
+//
+// Enabled probes are sent every 15 seconds of inactivity. Keepalive is
+// independent of MaxConnIdleTime: the pool still closes connections idle
+// for longer than MaxConnIdleTime, keepalive or not. With keepalive
+// disabled, set MaxConnIdleTime below the network's idle timeout instead,
+// so connections are retired before the network drops them. The option
+// replaces any Dialer set on ClientOptions, and applies to the TCP
+// connections returned by a WithDialer dialer.
+func WithKeepalive(enabled bool) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.Keepalive = &enabled
+	}
+}
+
+// WithDialer makes the client open its connections with dial, e.g. to
+// reach the servers through a SOCKS5 proxy with golang.org/x/net/proxy
+// or over a custom network. TLS from the URI or ClientOptions is still
+// negotiated by the driver on top of the returned connection.
+//
+// The option replaces any Dialer set on ClientOptions.
+func WithDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error)) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.Dialer = dial
+	}
+}
+
+// WithServedReadHook calls hook after every successful read with the
+// replica set member that served it, e.g. to count how often reads with
+// a secondaryPreferred read preference fall back to the primary, which
+// hints at unhealthy or lagging secondaries.
+//
+// The hook runs on the driver's monitoring path, so it must be fast and
+// must not run operations on the same client.
+func WithServedReadHook(hook func(ServedRead)) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.ServedReadHook = hook
+	}
+}
+
 // NewConnector creates a new MongoDB database connector using
 // the provided database name and connection URI.
 func NewConnector(
 	databaseName string,
 	uri string,
+	opts ...ConnectorOption,
 ) Connector[mongo.Database] {
 	c := &DatabaseConnector{
 		DatabaseName: databaseName,
 		URI:          uri,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
 	return c
 }
 
-// Connect creates a MongoDB client, applies the configured options,
-// and returns a handle to the configured database.
-func (c *DatabaseConnector) Connect() (*mongo.Database, error) {
+// BuildClientOptions returns the client options used by Connect.
+//
+// ClientOptions takes precedence over the URI; the connector-level
+// settings are then applied on top of a copy of it, so ClientOptions is
+// left unchanged and repeated calls build the same options.
+func (c *DatabaseConnector) BuildClientOptions() *options.ClientOptions {
 	opts := options.Client().ApplyURI(c.URI)
 	if c.ClientOptions != nil {
-		opts = c.ClientOptions
+		clone := *c.ClientOptions
+		opts = &clone
+	}
+	if c.PoolMonitor != nil {
+		opts = opts.SetPoolMonitor(c.PoolMonitor)
+	}
+	if c.ClientTimeout > 0 {
+		opts = opts.SetTimeout(c.ClientTimeout)
+	}
+	if c.MaxStaleness > 0 || c.HedgedReads != nil {
+		opts = opts.SetReadPreference(c.readPreference(opts.ReadPreference))
+	}
+	if c.Dialer != nil || c.Keepalive != nil {
+		opts = opts.SetDialer(c.dialer())
 	}
+	if c.ServedReadHook != nil {
+		commands, servers := servedReadMonitors(opts, c.ServedReadHook)
+		opts = opts.SetMonitor(commands).SetServerMonitor(servers)
+	}
+	return opts
+}
+
+// dialer returns the dialer combining Dialer and Keepalive, at least one
+// of which must be set.
+func (c *DatabaseConnector) dialer() options.ContextDialer {
+	if c.Dialer == nil {
+		// A negative KeepAlive disables the probes.
+		dialer := &net.Dialer{KeepAlive: -1}
+		if *c.Keepalive {
+			dialer.KeepAlive = keepaliveInterval
+		}
+		return dialer
+	}
+	if c.Keepalive == nil {
+		return dialFunc(c.Dialer)
+	}
+
+	enabled := *c.Keepalive
+	return dialFunc(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := c.Dialer(ctx, network, addr)
+		if tcp, ok := conn.(*net.TCPConn); ok && err == nil {
+			_ = tcp.SetKeepAlive(enabled)
+			if enabled {
+				_ = tcp.SetKeepAlivePeriod(keepaliveInterval)
+			}
+		}
+		return conn, err
+	})
+}
+
+// dialFunc adapts a dial function to the driver's ContextDialer.
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func (f dialFunc) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	return f(ctx, network, addr)
+}
 
-	client, err := mongo.Connect(opts)
+// readPreference derives a non-primary read preference from current with
+// the connector-level read settings applied. Mode and tag sets of current
+// are kept unless it is a primary read preference.
+func (c *DatabaseConnector) readPreference(current *readpref.ReadPref) *readpref.ReadPref {
+	mode := readpref.SecondaryPreferredMode
+	var rpOpts []readpref.Option
+	if current != nil && current.Mode() != readpref.PrimaryMode {
+		mode = current.Mode()
+		if tagSets := current.TagSets(); len(tagSets) > 0 {
+			rpOpts = append(rpOpts, readpref.WithTagSets(tagSets...))
+		}
+		if maxStaleness, ok := current.MaxStaleness(); ok {
+			rpOpts = append(rpOpts, readpref.WithMaxStaleness(maxStaleness))
+		}
+		if hedge := current.HedgeEnabled(); hedge != nil {
+			rpOpts = append(rpOpts, readpref.WithHedgeEnabled(*hedge))
+		}
+	}
+	if c.MaxStaleness > 0 {
+		rpOpts = append(rpOpts, readpref.WithMaxStaleness(c.MaxStaleness))
+	}
+	if c.HedgedReads != nil {
+		rpOpts = append(rpOpts, readpref.WithHedgeEnabled(*c.HedgedReads))
+	}
+
+	// New only fails for a primary mode with options, which is excluded above.
+	rp, _ := readpref.New(mode, rpOpts...)
+	return rp
+}
+
+// Connect creates a MongoDB client, applies the configured options,
+// and returns a handle to the configured database.
+func (c *DatabaseConnector) Connect() (*mongo.Database, error) {
+	client, err := mongo.Connect(c.BuildClientOptions())
 	if err != nil {
 		return nil, err
 	}
@@ -67,3 +294,58 @@ func (c *DatabaseConnector) Connect() (*mongo.Database, error) {
 
 	return client.Database(c.DatabaseName), nil
 }
+
+// ConnectDatabase connects like Connect and returns the database wrapped
+// with the database-level helpers.
+func (c *DatabaseConnector) ConnectDatabase() (*Database, error) {
+	db, err := c.Connect()
+	if err != nil {
+		return nil, err
+	}
+	return NewDatabase(db), nil
+}
+
+// ServerVersion returns the version of the connected server, e.g. "7.0.14",
+// as reported by the buildInfo command.
+//
+// It lets callers gate features on server capabilities and requires
+// Connect to have been called.
+func (c *DatabaseConnector) ServerVersion(ctx context.Context) (string, error) {
+	if c.Client == nil {
+		return "", ErrNotConnected
+	}
+
+	var info struct {
+		Version string `bson:"version"`
+	}
+	err := c.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "buildInfo", Value: 1}}).Decode(&info)
+	if err != nil {
+		return "", err
+	}
+	if info.Version == "" {
+		return "", fmt.Errorf("buildInfo returned no version")
+	}
+	return info.Version, nil
+}
+
+// IsMaster reports whether the server the client talks to is the primary
+// (a writable primary), along with that server's host, using the hello
+// command.
+//
+// On a standalone server host is empty, since only replica set members
+// report their own address. Connect must have been called.
+func (c *DatabaseConnector) IsMaster(ctx context.Context) (bool, string, error) {
+	if c.Client == nil {
+		return false, "", ErrNotConnected
+	}
+
+	var hello struct {
+		IsWritablePrimary bool   `bson:"isWritablePrimary"`
+		Me                string `bson:"me"`
+	}
+	err := c.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, "", err
+	}
+	return hello.IsWritablePrimary, hello.Me, nil
+}
@@ -0,0 +1,329 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+func TestConnectorOptions(t *testing.T) {
+	t.Run("WithPoolMonitor", func(t *testing.T) {
+		monitor := &event.PoolMonitor{Event: func(*event.PoolEvent) {}}
+		c := NewConnector("db", "mongodb://localhost:27017", WithPoolMonitor(monitor)).(*DatabaseConnector)
+
+		opts := c.BuildClientOptions()
+		if opts.PoolMonitor != monitor {
+			t.Fatal("expected pool monitor on client options")
+		}
+	})
+
+	t.Run("WithClientTimeout", func(t *testing.T) {
+		c := NewConnector("db", "mongodb://localhost:27017", WithClientTimeout(2*time.Second)).(*DatabaseConnector)
+
+		opts := c.BuildClientOptions()
+		if opts.Timeout == nil || *opts.Timeout != 2*time.Second {
+			t.Fatalf("expected 2s timeout, got %v", opts.Timeout)
+		}
+	})
+
+	t.Run("WithMaxStaleness", func(t *testing.T) {
+		c := NewConnector("db", "mongodb://localhost:27017", WithMaxStaleness(2*time.Minute)).(*DatabaseConnector)
+
+		rp := c.BuildClientOptions().ReadPreference
+		if rp == nil {
+			t.Fatal("expected read preference")
+		}
+		maxStaleness, ok := rp.MaxStaleness()
+		if !ok || maxStaleness != 2*time.Minute {
+			t.Fatalf("expected 2m max staleness, got %v (set=%v)", maxStaleness, ok)
+		}
+		if rp.Mode() != readpref.SecondaryPreferredMode {
+			t.Fatalf("expected secondaryPreferred, got %s", rp.Mode())
+		}
+	})
+
+	t.Run("WithMaxStaleness keeps URI mode", func(t *testing.T) {
+		c := NewConnector(
+			"db",
+			"mongodb://localhost:27017/?readPreference=nearest",
+			WithMaxStaleness(2*time.Minute),
+		).(*DatabaseConnector)
+
+		rp := c.BuildClientOptions().ReadPreference
+		if rp.Mode() != readpref.NearestMode {
+			t.Fatalf("expected nearest, got %s", rp.Mode())
+		}
+		if maxStaleness, _ := rp.MaxStaleness(); maxStaleness != 2*time.Minute {
+			t.Fatalf("expected 2m max staleness, got %v", maxStaleness)
+		}
+	})
+
+	t.Run("WithHedgedReads", func(t *testing.T) {
+		c := NewConnector(
+			"db",
+			"mongodb://localhost:27017/?readPreference=nearest",
+			WithHedgedReads(true),
+			WithMaxStaleness(2*time.Minute),
+		).(*DatabaseConnector)
+
+		rp := c.BuildClientOptions().ReadPreference
+		hedge := rp.HedgeEnabled()
+		if hedge == nil || !*hedge {
+			t.Fatalf("expected hedged reads enabled, got %v", hedge)
+		}
+		if rp.Mode() != readpref.NearestMode {
+			t.Fatalf("expected nearest, got %s", rp.Mode())
+		}
+		if maxStaleness, _ := rp.MaxStaleness(); maxStaleness != 2*time.Minute {
+			t.Fatalf("expected 2m max staleness, got %v", maxStaleness)
+		}
+
+		c = NewConnector("db", "mongodb://localhost:27017", WithHedgedReads(false)).(*DatabaseConnector)
+		hedge = c.BuildClientOptions().ReadPreference.HedgeEnabled()
+		if hedge == nil || *hedge {
+			t.Fatalf("expected hedged reads disabled, got %v", hedge)
+		}
+	})
+
+	t.Run("WithKeepalive", func(t *testing.T) {
+		for enabled, expected := range map[bool]time.Duration{true: keepaliveInterval, false: -1} {
+			c := NewConnector("db", "mongodb://localhost:27017", WithKeepalive(enabled)).(*DatabaseConnector)
+
+			dialer, ok := c.BuildClientOptions().Dialer.(*net.Dialer)
+			if !ok {
+				t.Fatalf("expected *net.Dialer, got %T", c.BuildClientOptions().Dialer)
+			}
+			if dialer.KeepAlive != expected {
+				t.Fatalf("expected keepalive %v, got %v", expected, dialer.KeepAlive)
+			}
+		}
+
+		c := NewConnector("db", "mongodb://localhost:27017").(*DatabaseConnector)
+		if dialer := c.BuildClientOptions().Dialer; dialer != nil {
+			t.Fatalf("expected driver dialer, got %T", dialer)
+		}
+	})
+}
+
+func TestWithDialer(t *testing.T) {
+	// The listener stands in for a server, so the driver's monitor has
+	// something to dial without a real deployment.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	dialed := make(chan string, 16)
+	record := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		select {
+		case dialed <- addr:
+		default:
+		}
+		var d net.Dialer
+		return d.DialContext(ctx, network, addr)
+	}
+
+	for _, keepalive := range []bool{false, true} {
+		opts := []ConnectorOption{WithDialer(record)}
+		if keepalive {
+			opts = append(opts, WithKeepalive(true))
+		}
+		uri := "mongodb://" + listener.Addr().String() + "/?directConnection=true"
+		db, err := NewConnector("db", uri, opts...).Connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		select {
+		case addr := <-dialed:
+			if addr != listener.Addr().String() {
+				t.Fatalf("expected a dial to %s, got %s", listener.Addr(), addr)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected the custom dialer to be used (keepalive %v)", keepalive)
+		}
+		_ = db.Client().Disconnect(context.Background())
+		for len(dialed) > 0 {
+			<-dialed
+		}
+	}
+}
+
+func TestPoolMonitor(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	monitor := &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e.Type)
+		},
+	}
+
+	db := testDatabase(t, WithPoolMonitor(monitor))
+	if _, err := db.ListCollectionNames(ctx, map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, e := range events {
+		if e == event.ConnectionCreated {
+			return
+		}
+	}
+	t.Fatalf("expected %s event, got %v", event.ConnectionCreated, events)
+}
+
+func TestClientTimeout(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t, WithClientTimeout(200*time.Millisecond))
+	_ = db.Collection("slow_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "slow_users")
+	for i := range 5 {
+		if err := model.Create(ctx, testUser{ID: fmt.Sprintf("%d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := model.FindMany(ctx, bson.D{{Key: "$where", Value: "sleep(100) || true"}})
+	if err == nil {
+		t.Fatal("expected slow operation to time out")
+	}
+	if !mongo.IsTimeout(err) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	ctx := context.Background()
+	uri := os.Getenv("MONGODB_URI")
+	dbName := os.Getenv("DATABASE_NAME")
+
+	c := NewConnector(dbName, uri).(*DatabaseConnector)
+	if _, err := c.ServerVersion(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got %v", err)
+	}
+
+	if uri == "" || dbName == "" {
+		t.Skip("env not set")
+	}
+	if _, err := c.Connect(); err != nil {
+		t.Fatalf("connect error: %v", err)
+	}
+	defer c.Client.Disconnect(ctx)
+
+	version, err := c.ServerVersion(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^\d+\.\d+`).MatchString(version) {
+		t.Fatalf("expected dotted version, got %q", version)
+	}
+}
+
+func TestIsMaster(t *testing.T) {
+	ctx := context.Background()
+	uri := os.Getenv("MONGODB_URI")
+	dbName := os.Getenv("DATABASE_NAME")
+
+	c := NewConnector(dbName, uri).(*DatabaseConnector)
+	if _, _, err := c.IsMaster(ctx); !errors.Is(err, ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got %v", err)
+	}
+
+	if uri == "" || dbName == "" {
+		t.Skip("env not set")
+	}
+	db, err := c.Connect()
+	if err != nil {
+		t.Fatalf("connect error: %v", err)
+	}
+	defer c.Client.Disconnect(ctx)
+	requireReplicaSet(t, db)
+
+	isPrimary, host, err := c.IsMaster(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var status struct {
+		Members []struct {
+			Name     string `bson:"name"`
+			StateStr string `bson:"stateStr"`
+		} `bson:"members"`
+	}
+	err = c.Client.Database("admin").RunCommand(ctx, bson.D{{Key: "replSetGetStatus", Value: 1}}).Decode(&status)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var primary string
+	for _, m := range status.Members {
+		if m.StateStr == "PRIMARY" {
+			primary = m.Name
+		}
+	}
+	if isPrimary != (host == primary) {
+		t.Fatalf("reported isPrimary=%v for %s, but the primary is %s", isPrimary, host, primary)
+	}
+}
+
+func TestLogPoolSaturation(t *testing.T) {
+	var buf bytes.Buffer
+	monitor := LogPoolSaturation(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	monitor.Event(&event.PoolEvent{
+		Type:        event.ConnectionPoolCreated,
+		Address:     "localhost:27017",
+		PoolOptions: &event.MonitorPoolOptions{MaxPoolSize: 2},
+	})
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Address: "localhost:27017"})
+	if buf.Len() != 0 {
+		t.Fatalf("unexpected log before saturation: %s", buf.String())
+	}
+
+	monitor.Event(&event.PoolEvent{Type: event.ConnectionCheckedOut, Address: "localhost:27017"})
+	if !strings.Contains(buf.String(), "pool saturated") {
+		t.Fatalf("expected saturation log, got %q", buf.String())
+	}
+
+	buf.Reset()
+	monitor.Event(&event.PoolEvent{
+		Type:    event.ConnectionCheckOutFailed,
+		Address: "localhost:27017",
+		Reason:  event.ReasonTimedOut,
+	})
+	if !strings.Contains(buf.String(), "checkout timed out") {
+		t.Fatalf("expected timeout log, got %q", buf.String())
+	}
+}
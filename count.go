@@ -0,0 +1,174 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CountCovered counts the documents matching filter, forcing the query
+// planner to use the index named by hint.
+//
+// When the index covers the filter the count is answered from the index
+// alone, which keeps hot count paths away from the documents.
+func (m *mongoModel[T, C]) CountCovered(ctx context.Context, filter any, hint string) (int64, error) {
+	defer m.track("CountCovered")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+	opts := options.Count().SetHint(hint)
+	if comment := m.queryComment("CountCovered"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	return m.collection.CountDocuments(ctx, filter, opts)
+}
+
+// CountMissingField counts the documents that do not have field at all,
+// e.g. to check a collection is ready for a migration. Documents where
+// the field is present but null are not counted.
+func (m *mongoModel[T, C]) CountMissingField(ctx context.Context, field string) (int64, error) {
+	defer m.track("CountMissingField")()
+
+	if err := validateFieldPath(field); err != nil {
+		return 0, err
+	}
+	opts := options.Count()
+	if comment := m.queryComment("CountMissingField"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	return m.collection.CountDocuments(ctx, bson.D{
+		{Key: field, Value: bson.D{{Key: "$exists", Value: false}}},
+	}, opts)
+}
+
+// CountByRange counts the documents whose field falls in each range
+// between consecutive boundaries, e.g. to spot hot shard key ranges. The
+// i-th count covers [boundaries[i], boundaries[i+1]), so there is one
+// count fewer than boundaries.
+//
+// boundaries must be sorted ascending and of comparable types. Documents
+// outside every range, or without field, are not counted.
+func (m *mongoModel[T, C]) CountByRange(ctx context.Context, field string, boundaries []any) ([]int64, error) {
+	defer m.track("CountByRange")()
+
+	if err := validateFieldPath(field); err != nil {
+		return nil, err
+	}
+	if len(boundaries) < 2 {
+		return nil, fmt.Errorf("at least 2 boundaries are required, got %d", len(boundaries))
+	}
+
+	// MinKey sorts before any boundary, so it is a valid catch-all
+	// bucket for the documents outside the ranges.
+	pipeline := Pipeline().
+		Bucket("$"+field, boundaries, bson.MinKey{}, nil).
+		Build()
+	opts := options.Aggregate()
+	if comment := m.queryComment("CountByRange"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	buckets, err := decodeCursor[struct {
+		ID    bson.RawValue `bson:"_id"`
+		Count int64         `bson:"count"`
+	}](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each bucket _id is its lower boundary, encoded like the pipeline.
+	raw, err := bson.Marshal(bson.D{{Key: "boundaries", Value: boundaries}})
+	if err != nil {
+		return nil, err
+	}
+	lower, err := bson.Raw(raw).Lookup("boundaries").Array().Values()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]int64, len(boundaries)-1)
+	for _, bucket := range buckets {
+		for i := range counts {
+			if bucket.ID.Equal(lower[i]) {
+				counts[i] = bucket.Count
+				break
+			}
+		}
+	}
+	return counts, nil
+}
+
+// EstimateCardinality estimates the number of distinct values of field
+// from a random sample of sampleSize documents, e.g. to decide whether a
+// UI filter should be a dropdown, without scanning the whole collection.
+//
+// The result is an estimate, not a count: the distinct values of the
+// sample are extrapolated to the estimated document count, scaling up
+// values seen only once by the square root of the sampling ratio (the
+// GEE estimator). It is exact when the sample covers the collection, and
+// rare values make it less precise. A missing or null field counts as
+// one value.
+func (m *mongoModel[T, C]) EstimateCardinality(ctx context.Context, field string, sampleSize int64) (int64, error) {
+	defer m.track("EstimateCardinality")()
+
+	if err := validateFieldPath(field); err != nil {
+		return 0, err
+	}
+	if sampleSize <= 0 {
+		return 0, fmt.Errorf("sample size must be positive, got %d", sampleSize)
+	}
+
+	countOpts := options.EstimatedDocumentCount()
+	if comment := m.queryComment("EstimateCardinality"); comment != nil {
+		countOpts = countOpts.SetComment(comment)
+	}
+	total, err := m.collection.EstimatedDocumentCount(ctx, countOpts)
+	if err != nil {
+		return 0, err
+	}
+
+	pipeline := Pipeline().
+		Stage(bson.D{{Key: "$sample", Value: bson.D{{Key: "size", Value: sampleSize}}}}).
+		Stage(bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + field},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}).
+		Build()
+	opts := options.Aggregate()
+	if comment := m.queryComment("EstimateCardinality"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	groups, err := decodeCursor[struct {
+		Count int64 `bson:"count"`
+	}](ctx, cursor)
+	if err != nil {
+		return 0, err
+	}
+
+	var sampled, singletons int64
+	for _, group := range groups {
+		sampled += group.Count
+		if group.Count == 1 {
+			singletons++
+		}
+	}
+	distinct := int64(len(groups))
+	if sampled == 0 || sampled >= total {
+		return distinct, nil
+	}
+
+	scale := math.Sqrt(float64(total) / float64(sampled))
+	estimate := int64(math.Round(scale*float64(singletons))) + distinct - singletons
+	return min(estimate, total), nil
+}
@@ -0,0 +1,228 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestCount(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("count_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "count_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "Dev"},
+		{ID: "2", Name: "Bob", Position: "QA"},
+		{ID: "3", Name: "Carol", Position: "Dev"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cases := []struct {
+		name   string
+		filter any
+		want   int64
+	}{
+		{"filter", bson.D{{Key: "position", Value: "Dev"}}, 2},
+		{"no match", bson.D{{Key: "position", Value: "Ops"}}, 0},
+		{"empty filter", bson.D{}, 3},
+		{"nil filter", nil, 3},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := model.Count(ctx, c.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Fatalf("expected %d, got %d", c.want, got)
+			}
+		})
+	}
+
+	t.Run("estimated", func(t *testing.T) {
+		got, err := model.EstimatedCount(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 3 {
+			t.Fatalf("expected 3, got %d", got)
+		}
+	})
+}
+
+func TestCountCovered(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("count_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "count_users")
+	for i := range 20 {
+		position := "Dev"
+		if i%2 == 0 {
+			position = "QA"
+		}
+		err := model.Create(ctx, testUser{ID: fmt.Sprintf("%d", i), Position: position})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err := db.Collection("count_users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "position", Value: 1}},
+		Options: options.Index().SetName("position_1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filter := bson.D{{Key: "position", Value: "QA"}}
+
+	hinted, err := model.CountCovered(ctx, filter, "position_1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unhinted, err := db.Collection("count_users").CountDocuments(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hinted != unhinted || hinted != 10 {
+		t.Fatalf("expected 10 for both counts, got hinted=%d unhinted=%d", hinted, unhinted)
+	}
+
+	var plan bson.M
+	err = db.RunCommand(ctx, bson.D{
+		{Key: "explain", Value: bson.D{
+			{Key: "aggregate", Value: "count_users"},
+			{Key: "pipeline", Value: bson.A{
+				bson.D{{Key: "$match", Value: filter}},
+				bson.D{{Key: "$group", Value: bson.D{
+					{Key: "_id", Value: 1},
+					{Key: "n", Value: bson.D{{Key: "$sum", Value: 1}}},
+				}}},
+			}},
+			{Key: "hint", Value: "position_1"},
+			{Key: "cursor", Value: bson.D{}},
+		}},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}).Decode(&plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(fmt.Sprint(plan), "position_1") {
+		t.Fatalf("expected plan to use position_1, got %v", plan)
+	}
+}
+
+func TestCountMissingField(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("missing_field").Drop(ctx)
+
+	coll := db.Collection("missing_field")
+	_, err := coll.InsertMany(ctx, []any{
+		bson.D{{Key: "_id", Value: "1"}, {Key: "email", Value: "alice@test.com"}},
+		bson.D{{Key: "_id", Value: "2"}, {Key: "email", Value: nil}},
+		bson.D{{Key: "_id", Value: "3"}},
+		bson.D{{Key: "_id", Value: "4"}},
+		bson.D{{Key: "_id", Value: "5"}, {Key: "name", Value: "Eve"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := New[testUser, testUser](db, "missing_field")
+	missing, err := model.CountMissingField(ctx, "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing != 3 {
+		t.Fatalf("expected 3 documents missing email, got %d", missing)
+	}
+}
+
+func TestCountByRange(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		model := &mongoModel[testUser, testUser]{}
+		if _, err := model.CountByRange(context.Background(), "age", []any{1}); err == nil {
+			t.Fatal("expected an error for a single boundary")
+		}
+		if _, err := model.CountByRange(context.Background(), "$age", []any{1, 2}); !errors.Is(err, ErrInvalidFieldPath) {
+			t.Fatalf("expected ErrInvalidFieldPath, got %v", err)
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("range_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "range_users")
+	for i, age := range []int{5, 12, 18, 19, 30, 64, 65, 90} {
+		if err := model.Create(ctx, testUser{ID: fmt.Sprint(i), Age: age}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := model.CountByRange(ctx, "age", []any{10, 18, 30, 65, 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 5 and 90 fall outside every range.
+	expected := []int64{1, 2, 2, 1}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("expected %v, got %v", expected, counts)
+	}
+}
+
+func TestEstimateCardinality(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		model := &mongoModel[testUser, testUser]{}
+		if _, err := model.EstimateCardinality(context.Background(), "age", 0); err == nil {
+			t.Fatal("expected an error for an empty sample")
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("cardinality_users").Drop(ctx)
+
+	docs := make([]any, 0, 1000)
+	for i := range 1000 {
+		docs = append(docs, testUser{ID: fmt.Sprint(i), Age: i % 50})
+	}
+	if _, err := db.Collection("cardinality_users").InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+	model := New[testUser, testUser](db, "cardinality_users")
+
+	t.Run("sampled", func(t *testing.T) {
+		estimate, err := model.EstimateCardinality(ctx, "age", 200)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate < 40 || estimate > 60 {
+			t.Fatalf("expected an estimate within 20%% of 50, got %d", estimate)
+		}
+	})
+
+	t.Run("whole collection", func(t *testing.T) {
+		estimate, err := model.EstimateCardinality(ctx, "age", 2000)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate != 50 {
+			t.Fatalf("expected exactly 50, got %d", estimate)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// CreateReturningID inserts a new document and returns its _id, which is
+// the generated one when WithIDGenerator is set and v has no _id.
+func (m *mongoModel[T, C]) CreateReturningID(ctx context.Context, v T) (any, error) {
+	defer m.track("CreateReturningID")()
+
+	return m.insertOne(ctx, "CreateReturningID", v)
+}
+
+// insertOne inserts v, generating its _id when configured, and audits the
+// write as op.
+func (m *mongoModel[T, C]) insertOne(ctx context.Context, op string, v T) (any, error) {
+	doc, err := m.withGeneratedID(v)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkDocSize(doc); err != nil {
+		return nil, err
+	}
+
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	insertOpts := options.InsertOne()
+	if comment := m.queryComment(op); comment != nil {
+		insertOpts = insertOpts.SetComment(comment)
+	}
+	result, err := m.collection.InsertOne(wctx, doc, insertOpts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return nil, err
+	}
+	m.audit(ctx, op, nil, doc)
+	if result == nil {
+		return nil, err
+	}
+	return result.InsertedID, err
+}
+
+// withGeneratedID returns the document to insert for v. Without an ID
+// generator, or when v already has an _id, that is v itself; otherwise
+// it is the encoded v with a generated _id as its first field.
+func (m *mongoModel[T, C]) withGeneratedID(v T) (any, error) {
+	if m.config.idGenerator == nil {
+		return v, nil
+	}
+
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if !missingID(bson.Raw(raw).Lookup("_id")) {
+		return v, nil
+	}
+	return replaceID(raw, m.config.idGenerator())
+}
+
+// withInsertedID returns v decoded back with id as its _id, so T needs
+// no setter.
+func withInsertedID[T any](v T, id any) (T, error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return v, err
+	}
+	doc, err := replaceID(raw, id)
+	if err != nil {
+		return v, err
+	}
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return v, err
+	}
+	var inserted T
+	if err := bson.Unmarshal(data, &inserted); err != nil {
+		return v, err
+	}
+	return inserted, nil
+}
+
+// replaceID returns the encoded document raw with id as its first field
+// in place of its _id.
+func replaceID(raw bson.Raw, id any) (bson.D, error) {
+	elems, err := raw.Elements()
+	if err != nil {
+		return nil, err
+	}
+	doc := bson.D{{Key: "_id", Value: id}}
+	for _, elem := range elems {
+		if elem.Key() == "_id" {
+			continue
+		}
+		doc = append(doc, bson.E{Key: elem.Key(), Value: elem.Value()})
+	}
+	return doc, nil
+}
+
+// missingID reports whether an _id value is absent, null or an empty string.
+func missingID(id bson.RawValue) bool {
+	switch id.Type {
+	case 0, bson.TypeNull, bson.TypeUndefined:
+		return true
+	case bson.TypeString:
+		return id.StringValue() == ""
+	}
+	return false
+}
+
+// CreateOrGet inserts doc, or returns the existing document matched by
+// filter when the insert fails with a duplicate-key error.
+//
+// The boolean result reports whether doc was inserted, in which case the
+// returned document carries the _id generated by WithIDGenerator, if any.
+func (m *mongoModel[T, C]) CreateOrGet(ctx context.Context, doc T, filter any) (T, bool, error) {
+	defer m.track("CreateOrGet")()
+
+	id, err := m.insertOne(ctx, "CreateOrGet", doc)
+	if err == nil {
+		if m.config.idGenerator == nil {
+			return doc, true, nil
+		}
+		inserted, err := withInsertedID(doc, id)
+		return inserted, true, err
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		var zero T
+		return zero, false, err
+	}
+
+	opts := withDefault(nil, m.queryComment("CreateOrGet"), func(o *options.FindOneOptions) *any { return &o.Comment })
+	existing, err := m.FindOne(ctx, filter, opts...)
+	if err != nil {
+		return existing, false, err
+	}
+	return existing, false, nil
+}
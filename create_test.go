@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestCreateOrGet(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("create_or_get").Drop(ctx)
+
+	model := New[testUser, testUser](db, "create_or_get")
+	filter := map[string]any{"_id": "1"}
+
+	user, created, err := model.CreateOrGet(ctx, testUser{ID: "1", Name: "Alice"}, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created {
+		t.Fatal("expected document to be created")
+	}
+	if user.Name != "Alice" {
+		t.Fatalf("expected Alice, got %s", user.Name)
+	}
+
+	user, created, err = model.CreateOrGet(ctx, testUser{ID: "1", Name: "Bob"}, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created {
+		t.Fatal("expected existing document")
+	}
+	if user.Name != "Alice" {
+		t.Fatalf("expected existing Alice, got %s", user.Name)
+	}
+}
+
+func TestWithIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("generated_ids").Drop(ctx)
+
+	ids := []string{"01ARZ3NDEKTSV4RRFFQ69G5FAV", "01ARZ3NDEKTSV4RRFFQ69G5FAW"}
+	next := 0
+	generate := func() string {
+		id := ids[next]
+		next++
+		return id
+	}
+
+	model := New[testUser, testUser](db, "generated_ids", WithIDGenerator(generate))
+
+	id, err := model.CreateReturningID(ctx, testUser{Name: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != ids[0] {
+		t.Fatalf("expected %s, got %v", ids[0], id)
+	}
+
+	user, err := model.FindOne(ctx, map[string]any{"name": "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != ids[0] {
+		t.Fatalf("expected stored _id %s, got %s", ids[0], user.ID)
+	}
+
+	if err := model.Create(ctx, testUser{Name: "Bob"}); err != nil {
+		t.Fatal(err)
+	}
+	user, err = model.FindOne(ctx, map[string]any{"name": "Bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != ids[1] {
+		t.Fatalf("expected stored _id %s, got %s", ids[1], user.ID)
+	}
+
+	id, err = model.CreateReturningID(ctx, testUser{ID: "explicit", Name: "Carol"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "explicit" || next != 2 {
+		t.Fatalf("expected explicit _id to be kept, got %v", id)
+	}
+
+	t.Run("CreateOrGet", func(t *testing.T) {
+		ids = append(ids, "01ARZ3NDEKTSV4RRFFQ69G5FAX")
+		user, created, err := model.CreateOrGet(ctx, testUser{Name: "Dave"}, map[string]any{"name": "Dave"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !created || user.ID != ids[2] || user.Name != "Dave" {
+			t.Fatalf("expected Dave created with _id %s, got %+v (created %v)", ids[2], user, created)
+		}
+	})
+}
+
+func TestWithGeneratedID(t *testing.T) {
+	model := &mongoModel[testUser, testUser]{
+		config: modelConfig{idGenerator: func() string { return "generated" }},
+	}
+
+	doc, err := model.withGeneratedID(testUser{Name: "Alice", Age: 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, ok := doc.(bson.D)
+	if !ok {
+		t.Fatalf("expected bson.D, got %T", doc)
+	}
+	if d[0].Key != "_id" || d[0].Value != "generated" {
+		t.Fatalf("expected generated _id first, got %v", d[0])
+	}
+	if len(d) != 5 {
+		t.Fatalf("expected all fields to be kept, got %v", d)
+	}
+
+	doc, err = model.withGeneratedID(testUser{ID: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc.(testUser); !ok {
+		t.Fatalf("expected document with _id to be unchanged, got %T", doc)
+	}
+
+	inserted, err := withInsertedID(testUser{Name: "Alice", Age: 30}, "generated")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := (testUser{ID: "generated", Name: "Alice", Age: 30}); inserted != expected {
+		t.Fatalf("expected %+v, got %+v", expected, inserted)
+	}
+}
@@ -0,0 +1,31 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// decodeCursor drains the cursor, decoding every document into V.
+//
+// The cursor is always closed, and an empty result yields an empty,
+// non-nil slice.
+func decodeCursor[V any](ctx context.Context, cursor *mongo.Cursor) ([]V, error) {
+	defer cursor.Close(ctx)
+
+	results := make([]V, 0)
+
+	for cursor.Next(ctx) {
+		var item V
+		if err := cursor.Decode(&item); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
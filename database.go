@@ -0,0 +1,122 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Database wraps a MongoDB database handle with database-level helpers.
+//
+// The underlying *mongo.Database is embedded, so every driver method
+// remains available alongside the helpers.
+type Database struct {
+	*mongo.Database
+
+	// ConfirmDrop must be set before DropDatabase removes the database,
+	// to guard against dropping one by accident.
+	ConfirmDrop bool
+}
+
+// NewDatabase wraps db with the database-level helpers.
+func NewDatabase(db *mongo.Database) *Database {
+	return &Database{Database: db}
+}
+
+// CreateClusteredCollection creates a collection clustered on _id, so
+// documents are stored in _id order and range scans on _id avoid a
+// secondary index. This benefits large, scan-heavy collections.
+//
+// Clustered collections require MongoDB 5.3 or later.
+func (d *Database) CreateClusteredCollection(ctx context.Context, name string) error {
+	opts := options.CreateCollection().SetClusteredIndex(bson.D{
+		{Key: "key", Value: bson.D{{Key: "_id", Value: 1}}},
+		{Key: "unique", Value: true},
+	})
+	return d.CreateCollection(ctx, name, opts)
+}
+
+// DropDatabase drops the database with all its collections and indexes.
+//
+// It fails with ErrDropNotConfirmed unless ConfirmDrop is set, which
+// makes tenant deletions and test cleanups explicit in the caller.
+func (d *Database) DropDatabase(ctx context.Context) error {
+	if !d.ConfirmDrop {
+		return ErrDropNotConfirmed
+	}
+	return d.Drop(ctx)
+}
+
+// Move moves the documents matching filter from one collection to
+// another in a single transaction, e.g. from "active" to "archived", and
+// returns how many were moved. Readers see either all of the documents
+// in from or all of them in to, never both or neither.
+//
+// Both collections must belong to d's client and exist beforehand, as
+// not every server version creates collections inside a transaction. A
+// document whose _id already exists in to fails the whole move. The
+// moved documents count toward the transaction size and time limits, so
+// narrow filter to move a large set in batches. Transactions require a
+// replica set or sharded cluster.
+func (d *Database) Move(ctx context.Context, from, to *mongo.Collection, filter any) (int64, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	var moved int64
+	err := d.WithTransaction(ctx, func(tx TransactionContext) error {
+		moved = 0
+		cursor, err := from.Find(tx, filter)
+		if err != nil {
+			return err
+		}
+		docs, err := decodeCursor[bson.Raw](tx, cursor)
+		if err != nil || len(docs) == 0 {
+			return err
+		}
+
+		ids := make(bson.A, 0, len(docs))
+		for _, doc := range docs {
+			ids = append(ids, doc.Lookup("_id"))
+		}
+		if _, err := to.InsertMany(tx, docs); err != nil {
+			return err
+		}
+		result, err := from.DeleteMany(tx, bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}})
+		if err != nil {
+			return err
+		}
+		moved = result.DeletedCount
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
+
+// AllIndexes describes the indexes of every collection in the database,
+// keyed by collection name, e.g. for database-wide index audits. Each
+// collection is described as by ExportIndexes, so the _id index is left
+// out and a collection without other indexes maps to an empty slice.
+//
+// Views have no indexes of their own and are skipped.
+func (d *Database) AllIndexes(ctx context.Context) (map[string][]IndexSpec, error) {
+	names, err := d.ListCollectionNames(ctx, bson.D{{Key: "type", Value: bson.D{{Key: "$ne", Value: "view"}}}})
+	if err != nil {
+		return nil, err
+	}
+
+	indexes := make(map[string][]IndexSpec, len(names))
+	for _, name := range names {
+		specs, err := exportIndexes(ctx, d.Collection(name))
+		if err != nil {
+			return nil, fmt.Errorf("collection %q: %w", name, err)
+		}
+		indexes[name] = specs
+	}
+	return indexes, nil
+}
@@ -0,0 +1,192 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestCreateClusteredCollection(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	requireServerVersion(t, db.Database, 5, 3)
+	_ = db.Collection("clustered_events").Drop(ctx)
+
+	if err := db.CreateClusteredCollection(ctx, "clustered_events"); err != nil {
+		t.Fatal(err)
+	}
+
+	specs, err := db.ListCollectionSpecifications(ctx, bson.D{{Key: "name", Value: "clustered_events"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 1 {
+		t.Fatalf("expected 1 collection, got %d", len(specs))
+	}
+
+	key, err := specs[0].Options.LookupErr("clusteredIndex", "key", "_id")
+	if err != nil {
+		t.Fatalf("expected clustered index on _id, got options %s", specs[0].Options)
+	}
+	if key.AsInt64() != 1 {
+		t.Fatalf("expected ascending clustered key, got %s", key)
+	}
+}
+
+func TestDropDatabase(t *testing.T) {
+	ctx := context.Background()
+	uri := os.Getenv("MONGODB_URI")
+	if uri == "" {
+		t.Skip("env not set")
+	}
+
+	name := "tmp_drop_" + bson.NewObjectID().Hex()
+	connector := NewConnector(name, uri).(*DatabaseConnector)
+	db, err := connector.ConnectDatabase()
+	if err != nil {
+		t.Fatalf("connect error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Drop(ctx)
+		_ = connector.Client.Disconnect(ctx)
+	})
+
+	if _, err := db.Collection("items").InsertOne(ctx, bson.D{{Key: "n", Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+
+	listed := func() bool {
+		names, err := connector.Client.ListDatabaseNames(ctx, bson.D{{Key: "name", Value: name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(names) > 0
+	}
+	if !listed() {
+		t.Fatalf("expected database %s to exist", name)
+	}
+
+	if err := db.DropDatabase(ctx); !errors.Is(err, ErrDropNotConfirmed) {
+		t.Fatalf("expected ErrDropNotConfirmed, got %v", err)
+	}
+	if !listed() {
+		t.Fatal("expected unconfirmed drop to keep the database")
+	}
+
+	db.ConfirmDrop = true
+	if err := db.DropDatabase(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if listed() {
+		t.Fatalf("expected database %s to be dropped", name)
+	}
+}
+
+func TestMove(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	requireReplicaSet(t, db.Database)
+
+	for _, name := range []string{"move_active", "move_archived"} {
+		_ = db.Collection(name).Drop(ctx)
+		if err := db.CreateCollection(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	active := New[testUser, testUser](db.Database, "move_active")
+	archived := New[testUser, testUser](db.Database, "move_archived")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "left"},
+		{ID: "2", Name: "Bob", Position: "left"},
+		{ID: "3", Name: "Carol", Position: "Dev"},
+	} {
+		if err := active.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	left := bson.D{{Key: "position", Value: "left"}}
+
+	moved, err := db.Move(ctx, db.Collection("move_active"), db.Collection("move_archived"), left)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved != 2 {
+		t.Fatalf("expected 2 moved, got %d", moved)
+	}
+	if remaining, _ := active.FindMany(ctx, bson.D{}); len(remaining) != 1 || remaining[0].ID != "3" {
+		t.Fatalf("expected only Carol to remain, got %+v", remaining)
+	}
+	if copied, _ := archived.FindMany(ctx, left); len(copied) != 2 {
+		t.Fatalf("expected 2 archived users, got %+v", copied)
+	}
+
+	t.Run("rolled back", func(t *testing.T) {
+		// Carol is already archived, so the insert fails and nothing moves.
+		if err := archived.Create(ctx, testUser{ID: "3", Name: "Carol"}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Move(ctx, db.Collection("move_active"), db.Collection("move_archived"), nil); err == nil {
+			t.Fatal("expected the move to fail on the duplicate _id")
+		}
+		if remaining, _ := active.FindMany(ctx, bson.D{}); len(remaining) != 1 {
+			t.Fatalf("expected the source untouched, got %+v", remaining)
+		}
+	})
+}
+
+func TestAllIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	for _, name := range []string{"all_indexes_users", "all_indexes_orders", "all_indexes_view"} {
+		_ = db.Collection(name).Drop(ctx)
+	}
+
+	_, err := db.Collection("all_indexes_users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetName("email_1").SetUnique(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = db.Collection("all_indexes_orders").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}},
+		Options: options.Index().SetName("user_created"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.CreateView(ctx, "all_indexes_view", "all_indexes_users", mongo.Pipeline{}); err != nil {
+		t.Fatal(err)
+	}
+
+	indexes, err := db.AllIndexes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := func(specs []IndexSpec) []string {
+		out := make([]string, 0, len(specs))
+		for _, spec := range specs {
+			out = append(out, spec.Name)
+		}
+		return out
+	}
+	if got := names(indexes["all_indexes_users"]); !reflect.DeepEqual(got, []string{"email_1"}) {
+		t.Fatalf("expected [email_1] on users, got %v", got)
+	}
+	if got := names(indexes["all_indexes_orders"]); !reflect.DeepEqual(got, []string{"user_created"}) {
+		t.Fatalf("expected [user_created] on orders, got %v", got)
+	}
+	if !indexes["all_indexes_users"][0].Unique {
+		t.Fatal("expected the email index to be unique")
+	}
+	if _, ok := indexes["all_indexes_view"]; ok {
+		t.Fatal("expected views to be skipped")
+	}
+}
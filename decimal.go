@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Decimal parses a decimal string such as "19.99" into a Decimal128,
+// the BSON type for exact monetary values. Unlike float64, the value is
+// stored and summed without binary rounding.
+func Decimal(s string) (bson.Decimal128, error) {
+	d, err := bson.ParseDecimal128(s)
+	if err != nil {
+		return bson.Decimal128{}, fmt.Errorf("invalid decimal %q: %w", s, err)
+	}
+	return d, nil
+}
+
+// SumDecimal returns the exact sum of field over the documents matching
+// filter, or zero when nothing matches.
+//
+// Values are converted with $toDecimal before summing, so fields stored
+// as integers or doubles are added in decimal arithmetic too; documents
+// where field is missing or null are skipped.
+func (m *mongoModel[T, C]) SumDecimal(ctx context.Context, field string, filter any) (bson.Decimal128, error) {
+	defer m.track("SumDecimal")()
+
+	if err := validateFieldPath(field); err != nil {
+		return bson.Decimal128{}, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("SumDecimal"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, mongo.Pipeline{
+		{{Key: "$match", Value: filter}},
+		{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: nil},
+			{Key: "total", Value: bson.D{{Key: "$sum", Value: bson.D{{Key: "$toDecimal", Value: "$" + field}}}}},
+		}}},
+		// $sum yields an integer 0 when every value is null.
+		{{Key: "$project", Value: bson.D{{Key: "total", Value: bson.D{{Key: "$toDecimal", Value: "$total"}}}}}},
+	}, opts)
+	if err != nil {
+		return bson.Decimal128{}, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+
+	results, err := decodeCursor[struct {
+		Total bson.Decimal128 `bson:"total"`
+	}](ctx, cursor)
+	if err != nil {
+		return bson.Decimal128{}, err
+	}
+	if len(results) == 0 {
+		return Decimal("0")
+	}
+	return results[0].Total, nil
+}
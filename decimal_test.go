@@ -0,0 +1,88 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testPayment struct {
+	ID     string          `bson:"_id"`
+	Status string          `bson:"status"`
+	Amount bson.Decimal128 `bson:"amount"`
+}
+
+func TestDecimal(t *testing.T) {
+	d, err := Decimal("19.99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.String() != "19.99" {
+		t.Fatalf("expected 19.99, got %s", d)
+	}
+
+	if _, err := Decimal("12,50"); err == nil {
+		t.Fatal("expected invalid decimal error")
+	}
+}
+
+func TestSumDecimal(t *testing.T) {
+	t.Run("invalid field", func(t *testing.T) {
+		model := &mongoModel[testPayment, testPayment]{}
+		if _, err := model.SumDecimal(context.Background(), "$amount", nil); !errors.Is(err, ErrInvalidFieldPath) {
+			t.Fatalf("expected ErrInvalidFieldPath, got %v", err)
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("decimal_payments").Drop(ctx)
+
+	model := New[testPayment, testPayment](db, "decimal_payments")
+	// 0.1 + 0.2 is 0.30000000000000004 in float64.
+	for i, amount := range []string{"0.1", "0.2", "1000000.01", "5"} {
+		d, err := Decimal(amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		status := "paid"
+		if i == 3 {
+			status = "refunded"
+		}
+		if err := model.Create(ctx, testPayment{ID: amount, Status: status, Amount: d}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("round trip", func(t *testing.T) {
+		p, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: "0.1"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Amount.String() != "0.1" {
+			t.Fatalf("expected 0.1, got %s", p.Amount)
+		}
+	})
+
+	t.Run("exact sum", func(t *testing.T) {
+		total, err := model.SumDecimal(ctx, "amount", bson.D{{Key: "status", Value: "paid"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total.String() != "1000000.31" {
+			t.Fatalf("expected 1000000.31, got %s", total)
+		}
+	})
+
+	t.Run("no match", func(t *testing.T) {
+		total, err := model.SumDecimal(ctx, "amount", bson.D{{Key: "status", Value: "pending"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total.String() != "0" {
+			t.Fatalf("expected 0, got %s", total)
+		}
+	})
+}
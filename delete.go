@@ -0,0 +1,39 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// DeleteExpired deletes the documents whose date field is before
+// olderThan and returns how many were removed.
+//
+// It is a manual alternative to a TTL index for when cleanup must run
+// at a controlled time. Documents without the field, or where it is not
+// a date, are kept.
+func (m *mongoModel[T, C]) DeleteExpired(ctx context.Context, field string, olderThan time.Time) (int64, error) {
+	defer m.track("DeleteExpired")()
+
+	if err := validateFieldPath(field); err != nil {
+		return 0, err
+	}
+
+	filter := bson.D{{Key: field, Value: bson.D{{Key: "$lt", Value: olderThan}}}}
+	opts := options.DeleteMany()
+	if comment := m.queryComment("DeleteExpired"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.DeleteMany(wctx, filter, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return 0, err
+	}
+	m.audit(ctx, "DeleteExpired", filter, nil)
+	return result.DeletedCount, err
+}
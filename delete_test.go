@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestDeleteExpired(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("expired_events").Drop(ctx)
+
+	model := New[testEvent, testEvent](db, "expired_events")
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	for _, e := range []testEvent{
+		{ID: "old", CreatedAt: now.Add(-48 * time.Hour)},
+		{ID: "older", CreatedAt: now.Add(-72 * time.Hour)},
+		{ID: "recent", CreatedAt: now.Add(-time.Hour)},
+		{ID: "future", CreatedAt: now.Add(time.Hour)},
+	} {
+		if err := model.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deleted, err := model.DeleteExpired(ctx, "created_at", now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("expected 2 deleted, got %d", deleted)
+	}
+
+	events, err := model.FindMany(ctx, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var remaining []string
+	for _, e := range events {
+		remaining = append(remaining, e.ID)
+	}
+	sort.Strings(remaining)
+	if !reflect.DeepEqual(remaining, []string{"future", "recent"}) {
+		t.Fatalf("unexpected remaining documents %v", remaining)
+	}
+}
@@ -0,0 +1,160 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EncryptedSubtype is the user-defined BSON binary subtype of values
+// sealed by EncryptValue, which keeps them apart from the driver's own
+// client-side field level encryption subtype.
+const EncryptedSubtype byte = 0x80
+
+// EncryptValue seals v with AES-GCM under key, which must be 16, 24 or 32
+// bytes long, and returns it as a binary value to store in place of the
+// plaintext field. Any value BSON can encode may be sealed.
+func EncryptValue(key []byte, v any) (bson.Binary, error) {
+	plaintext, err := bson.Marshal(bson.D{{Key: "v", Value: v}})
+	if err != nil {
+		return bson.Binary{}, err
+	}
+	return encrypt(key, plaintext)
+}
+
+// DecryptValue opens a value sealed by EncryptValue with key and decodes
+// the plaintext into out, which must be a pointer. A wrong key or a
+// tampered value fails with ErrDecryptionFailed.
+func DecryptValue(key []byte, bin bson.Binary, out any) error {
+	plaintext, err := decrypt(key, bin)
+	if err != nil {
+		return err
+	}
+	return bson.Raw(plaintext).Lookup("v").Unmarshal(out)
+}
+
+// RotateEncryption re-encrypts the given fields of every document from
+// oldKey to newKey, streaming the collection so memory use does not grow
+// with its size. It returns the number of documents rotated.
+//
+// Fields may be dotted paths and must hold values sealed by EncryptValue;
+// documents lacking them are skipped. Values already sealed under newKey
+// are left as is, so a rotation stopped by cancelling ctx or by an error
+// can simply be run again. The count of documents rotated so far is
+// returned along with the error.
+func (m *mongoModel[T, C]) RotateEncryption(ctx context.Context, oldKey, newKey []byte, fields []string) (int64, error) {
+	defer m.track("RotateEncryption")()
+
+	if _, err := newGCM(newKey); err != nil {
+		return 0, err
+	}
+	anyField := make(bson.A, 0, len(fields))
+	projection := bson.D{{Key: "_id", Value: 1}}
+	for _, field := range fields {
+		if err := validateFieldPath(field); err != nil {
+			return 0, err
+		}
+		anyField = append(anyField, bson.D{{Key: field, Value: bson.D{{Key: "$type", Value: "binData"}}}})
+		projection = append(projection, bson.E{Key: field, Value: 1})
+	}
+	if len(fields) == 0 {
+		return 0, nil
+	}
+
+	filter := bson.D{{Key: "$or", Value: anyField}}
+	findOpts := options.Find().SetProjection(projection)
+	updateOpts := options.UpdateOne()
+	if comment := m.queryComment("RotateEncryption"); comment != nil {
+		findOpts = findOpts.SetComment(comment)
+		updateOpts = updateOpts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(context.WithoutCancel(ctx))
+
+	var rotated int64
+	for cursor.Next(ctx) {
+		set := bson.D{}
+		for _, field := range fields {
+			subtype, data, ok := cursor.Current.Lookup(strings.Split(field, ".")...).BinaryOK()
+			if !ok || subtype != EncryptedSubtype {
+				continue
+			}
+			bin := bson.Binary{Subtype: subtype, Data: data}
+			if _, err := decrypt(newKey, bin); err == nil {
+				continue
+			}
+			plaintext, err := decrypt(oldKey, bin)
+			if err != nil {
+				return rotated, fmt.Errorf("document %v, field %s: %w", cursor.Current.Lookup("_id"), field, err)
+			}
+			reencrypted, err := encrypt(newKey, plaintext)
+			if err != nil {
+				return rotated, err
+			}
+			set = append(set, bson.E{Key: field, Value: reencrypted})
+		}
+		if len(set) == 0 {
+			continue
+		}
+
+		idFilter := bson.D{{Key: "_id", Value: cursor.Current.Lookup("_id")}}
+		update := bson.D{{Key: "$set", Value: set}}
+		wctx, cancel := m.writeContext(ctx)
+		_, err := m.collection.UpdateOne(wctx, idFilter, update, updateOpts)
+		cancel()
+		if err != nil {
+			return rotated, writeConcernTimeout(err)
+		}
+		m.audit(ctx, "RotateEncryption", idFilter, update)
+		rotated++
+	}
+	return rotated, cursor.Err()
+}
+
+// newGCM returns the AES-GCM cipher for key.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt seals plaintext under key as an EncryptedSubtype binary.
+func encrypt(key, plaintext []byte) (bson.Binary, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return bson.Binary{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return bson.Binary{}, err
+	}
+	return bson.Binary{Subtype: EncryptedSubtype, Data: gcm.Seal(nonce, nonce, plaintext, nil)}, nil
+}
+
+// decrypt opens a binary sealed by encrypt under key.
+func decrypt(key []byte, bin bson.Binary) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if bin.Subtype != EncryptedSubtype || len(bin.Data) < gcm.NonceSize() {
+		return nil, ErrDecryptionFailed
+	}
+	nonce, ciphertext := bin.Data[:gcm.NonceSize()], bin.Data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
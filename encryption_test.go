@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testSecret struct {
+	ID  string      `bson:"_id"`
+	SSN bson.Binary `bson:"ssn"`
+}
+
+func TestEncryptValue(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	bin, err := EncryptValue(key, "123-45-6789")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bin.Subtype != EncryptedSubtype {
+		t.Fatalf("expected subtype %x, got %x", EncryptedSubtype, bin.Subtype)
+	}
+
+	var got string
+	if err := DecryptValue(key, bin, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != "123-45-6789" {
+		t.Fatalf("expected 123-45-6789, got %q", got)
+	}
+
+	t.Run("wrong key", func(t *testing.T) {
+		wrong := bytes.Repeat([]byte{2}, 32)
+		if err := DecryptValue(wrong, bin, &got); !errors.Is(err, ErrDecryptionFailed) {
+			t.Fatalf("expected ErrDecryptionFailed, got %v", err)
+		}
+	})
+}
+
+func TestRotateEncryption(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("rotate_secrets").Drop(ctx)
+
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+
+	model := New[testSecret, testSecret](db, "rotate_secrets")
+	for _, id := range []string{"1", "2"} {
+		ssn, err := EncryptValue(oldKey, "ssn-"+id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := model.Create(ctx, testSecret{ID: id, SSN: ssn}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rotated, err := model.RotateEncryption(ctx, oldKey, newKey, []string{"ssn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 2 {
+		t.Fatalf("expected 2 rotated, got %d", rotated)
+	}
+
+	doc, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ssn string
+	if err := DecryptValue(newKey, doc.SSN, &ssn); err != nil {
+		t.Fatal(err)
+	}
+	if ssn != "ssn-1" {
+		t.Fatalf("expected ssn-1, got %q", ssn)
+	}
+	if err := DecryptValue(oldKey, doc.SSN, &ssn); !errors.Is(err, ErrDecryptionFailed) {
+		t.Fatalf("expected ErrDecryptionFailed with the old key, got %v", err)
+	}
+
+	t.Run("rerun", func(t *testing.T) {
+		rotated, err := model.RotateEncryption(ctx, oldKey, newKey, []string{"ssn"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rotated != 0 {
+			t.Fatalf("expected 0 rotated, got %d", rotated)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := model.RotateEncryption(cancelled, newKey, oldKey, []string{"ssn"}); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package mongodb
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// ErrNotFound is returned when no document matches a query.
+//
+// It is the driver's mongo.ErrNoDocuments, so errors.Is matches either.
+var ErrNotFound = mongo.ErrNoDocuments
+
+// ErrInvalidFieldPath is returned when a field path is empty, has an empty
+// segment, or contains a segment starting with the "$" operator prefix.
+var ErrInvalidFieldPath = errors.New("invalid field path")
+
+// ErrInvalidPipeline is returned when a pipeline cannot be used for the
+// requested operation, e.g. an update pipeline with a $match stage.
+var ErrInvalidPipeline = errors.New("invalid pipeline")
+
+// ErrNotConnected is returned by connector methods that need a client
+// when Connect has not been called yet.
+var ErrNotConnected = errors.New("connector is not connected")
+
+// ErrInvalidGranularity is returned when a date bucket granularity is
+// not supported.
+var ErrInvalidGranularity = errors.New("invalid granularity")
+
+// ErrUnknownDiscriminator is returned when a polymorphic document has a
+// discriminator value with no registered type.
+var ErrUnknownDiscriminator = errors.New("unknown discriminator")
+
+// ErrInvalidFilter is returned when a filter cannot be parsed.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// ErrSchemaViolation is returned when a document does not satisfy a JSON
+// schema. The violations are reported as *SchemaError values.
+var ErrSchemaViolation = errors.New("schema violation")
+
+// ErrDropNotConfirmed is returned when dropping a database that was not
+// explicitly confirmed.
+var ErrDropNotConfirmed = errors.New("drop not confirmed")
+
+// ErrMissingIndexes is returned when indexes required by RequireIndexes
+// do not exist.
+var ErrMissingIndexes = errors.New("missing indexes")
+
+// ErrDecryptionFailed is returned when an encrypted value cannot be
+// opened, because the key is wrong or the value was tampered with.
+var ErrDecryptionFailed = errors.New("decryption failed")
+
+// ErrWriteConcernTimeout is returned when a write was applied but timed
+// out waiting for its write concern, so it may not be durable yet.
+var ErrWriteConcernTimeout = errors.New("write concern timeout")
+
+// ErrConcurrentModification is returned when a read-modify-write keeps
+// losing the race against other writers of the same document.
+var ErrConcurrentModification = errors.New("concurrent modification")
+
+// ErrDocumentTooLarge is returned when a document to insert encodes to
+// more bytes than the limit set with WithMaxDocSize.
+var ErrDocumentTooLarge = errors.New("document too large")
+
+// ErrIDConversionConflict is returned by ConvertIDsToObjectID when a
+// document exists under both its string and ObjectID _id with different
+// contents, so neither can be removed without losing writes.
+var ErrIDConversionConflict = errors.New("id conversion conflict")
+
+// ErrNestedTransaction is returned by operations that must run in a
+// transaction of their own when ctx already carries a session.
+var ErrNestedTransaction = errors.New("nested transaction")
@@ -0,0 +1,202 @@
+package mongodb
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ExportJSONL streams every document matching filter to w as
+// newline-delimited relaxed Extended JSON, and returns the number of
+// documents written.
+//
+// Documents are written straight from the cursor, so memory use is
+// bounded by the cursor batch size rather than the result size. The
+// output can be read back with ImportJSONL or bson.UnmarshalExtJSON.
+func (m *mongoModel[T, C]) ExportJSONL(ctx context.Context, filter any, w io.Writer) (int64, error) {
+	defer m.track("ExportJSONL")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := options.Find()
+	if comment := m.queryComment("ExportJSONL"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	buf := bufio.NewWriter(w)
+	var count int64
+	for cursor.Next(ctx) {
+		line, err := bson.MarshalExtJSON(cursor.Current, false, false)
+		if err != nil {
+			return count, err
+		}
+		line = append(line, '\n')
+		if _, err := buf.Write(line); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+
+	return count, buf.Flush()
+}
+
+// AggregateToJSON runs pipeline and streams its results to w as a JSON
+// array of relaxed Extended JSON documents, returning the number of
+// documents written. An empty result is written as [].
+//
+// The array is written incrementally from the cursor, so large results,
+// e.g. analytics served over HTTP, are never buffered in memory. When
+// the aggregation fails midway the array written so far is left
+// unterminated.
+func (m *mongoModel[T, C]) AggregateToJSON(ctx context.Context, pipeline mongo.Pipeline, w io.Writer) (int64, error) {
+	defer m.track("AggregateToJSON")()
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("AggregateToJSON"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	buf := bufio.NewWriter(w)
+	if err := buf.WriteByte('['); err != nil {
+		return 0, err
+	}
+	var count int64
+	for cursor.Next(ctx) {
+		if count > 0 {
+			if err := buf.WriteByte(','); err != nil {
+				return count, err
+			}
+		}
+		doc, err := bson.MarshalExtJSON(cursor.Current, false, false)
+		if err != nil {
+			return count, err
+		}
+		if _, err := buf.Write(doc); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+	if err := buf.WriteByte(']'); err != nil {
+		return count, err
+	}
+
+	return count, buf.Flush()
+}
+
+// ExportCSV streams every document matching filter to w as CSV, one row
+// per document, and returns the number of rows written after the header.
+//
+// The header row lists columns, and each column is a field path that may
+// use dots to reach embedded fields, e.g. "address.city". Missing fields
+// are written as empty cells. Strings, numbers and booleans are written
+// as is, dates as RFC 3339 in UTC, ObjectIDs as hex, and any other value
+// as relaxed Extended JSON.
+func (m *mongoModel[T, C]) ExportCSV(
+	ctx context.Context,
+	filter any,
+	columns []string,
+	w io.Writer,
+) (int64, error) {
+	defer m.track("ExportCSV")()
+
+	projection := bson.D{}
+	for _, column := range columns {
+		if err := validateFieldPath(column); err != nil {
+			return 0, err
+		}
+		projection = append(projection, bson.E{Key: column, Value: 1})
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return 0, err
+	}
+
+	opts := options.Find().SetProjection(projection)
+	if comment := m.queryComment("ExportCSV"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var count int64
+	row := make([]string, len(columns))
+	for cursor.Next(ctx) {
+		for i, column := range columns {
+			value, err := cursor.Current.LookupErr(strings.Split(column, ".")...)
+			if err != nil {
+				row[i] = ""
+				continue
+			}
+			row[i] = csvCell(value)
+		}
+		if err := writer.Write(row); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Err(); err != nil {
+		return count, err
+	}
+
+	writer.Flush()
+	return count, writer.Error()
+}
+
+// csvCell formats a BSON value as a CSV cell.
+func csvCell(value bson.RawValue) string {
+	switch value.Type {
+	case bson.TypeNull, bson.TypeUndefined:
+		return ""
+	case bson.TypeString:
+		return value.StringValue()
+	case bson.TypeInt32:
+		return strconv.FormatInt(int64(value.Int32()), 10)
+	case bson.TypeInt64:
+		return strconv.FormatInt(value.Int64(), 10)
+	case bson.TypeDouble:
+		return strconv.FormatFloat(value.Double(), 'f', -1, 64)
+	case bson.TypeDecimal128:
+		return value.Decimal128().String()
+	case bson.TypeBoolean:
+		return strconv.FormatBool(value.Boolean())
+	case bson.TypeDateTime:
+		return value.Time().UTC().Format(time.RFC3339Nano)
+	case bson.TypeObjectID:
+		return value.ObjectID().Hex()
+	}
+	return value.String()
+}
@@ -0,0 +1,190 @@
+package mongodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func seedExportUsers(t *testing.T, model DefaultModel[testUser, testUser]) []testUser {
+	t.Helper()
+	users := []testUser{
+		{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30, Position: "Dev"},
+		{ID: "2", Name: "Bob", Email: "bob@test.com", Age: 35, Position: "QA"},
+		{ID: "3", Name: "Carol", Email: "carol@test.com", Age: 40, Position: "Dev"},
+	}
+	for _, u := range users {
+		if err := model.Create(context.Background(), u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return users
+}
+
+func TestExportJSONL(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("export_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "export_users")
+	users := seedExportUsers(t, model)
+
+	var buf bytes.Buffer
+	count, err := model.ExportJSONL(ctx, map[string]any{}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != int64(len(users)) {
+		t.Fatalf("expected %d documents, got %d", len(users), count)
+	}
+
+	byID := make(map[string]testUser)
+	for _, u := range users {
+		byID[u.ID] = u
+	}
+
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		lines++
+		var u testUser
+		if err := bson.UnmarshalExtJSON(scanner.Bytes(), false, &u); err != nil {
+			t.Fatalf("line %d: %v", lines, err)
+		}
+		if byID[u.ID] != u {
+			t.Fatalf("expected %+v, got %+v", byID[u.ID], u)
+		}
+	}
+	if lines != len(users) {
+		t.Fatalf("expected %d lines, got %d", len(users), lines)
+	}
+
+	buf.Reset()
+	count, err = model.ExportJSONL(ctx, map[string]any{"position": "QA"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 document, got %d", count)
+	}
+}
+
+func TestAggregateToJSON(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("json_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "json_users")
+	seedExportUsers(t, model)
+
+	t.Run("results", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := model.AggregateToJSON(ctx, Pipeline().
+			Match(bson.D{{Key: "position", Value: "Dev"}}).
+			Stage(bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}}).
+			Stage(bson.D{{Key: "$project", Value: bson.D{{Key: "name", Value: 1}, {Key: "age", Value: 1}}}}).
+			Build(), &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatalf("expected 2 documents, got %d", count)
+		}
+
+		var got []map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON %s: %v", buf.String(), err)
+		}
+		expected := []map[string]any{
+			{"_id": "1", "name": "Alice", "age": 30.0},
+			{"_id": "3", "name": "Carol", "age": 40.0},
+		}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := model.AggregateToJSON(ctx, Pipeline().Match(bson.D{{Key: "position", Value: "CEO"}}).Build(), &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 0 || buf.String() != "[]" {
+			t.Fatalf("expected [] with 0 documents, got %s with %d", buf.String(), count)
+		}
+	})
+}
+
+func TestExportCSV(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("csv_customers").Drop(ctx)
+
+	model := New[testCustomer, testCustomer](db, "csv_customers")
+	for _, c := range []testCustomer{
+		{ID: "1", Name: "Alice", Address: testAddress{City: "Lisbon"}},
+		{ID: "2", Name: "Bob, Jr.", Address: testAddress{City: "Porto"}},
+	} {
+		if err := model.Create(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var buf bytes.Buffer
+	count, err := model.ExportCSV(ctx, map[string]any{}, []string{"_id", "name", "address.city", "missing"}, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected 2 rows, got %d", count)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"_id", "name", "address.city", "missing"},
+		{"1", "Alice", "Lisbon", ""},
+		{"2", "Bob, Jr.", "Porto", ""},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Fatalf("expected %v, got %v", expected, records)
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+	oid := bson.NewObjectID()
+
+	cases := []struct {
+		value    any
+		expected string
+	}{
+		{"text", "text"},
+		{int32(42), "42"},
+		{int64(-7), "-7"},
+		{1.5, "1.5"},
+		{true, "true"},
+		{at, "2024-03-01T12:30:00Z"},
+		{oid, oid.Hex()},
+		{nil, ""},
+	}
+	for _, c := range cases {
+		raw, err := bson.Marshal(bson.D{{Key: "v", Value: c.value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := csvCell(bson.Raw(raw).Lookup("v")); got != c.expected {
+			t.Fatalf("expected %q for %v, got %q", c.expected, c.value, got)
+		}
+	}
+}
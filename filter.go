@@ -0,0 +1,17 @@
+package mongodb
+
+import "go.mongodb.org/mongo-driver/v2/bson"
+
+// InFilter builds a {field: {$in: values}} filter from a typed slice,
+// without converting it to []any first:
+//
+//	filter := InFilter("_id", ids)
+//
+// A nil or empty slice matches no documents.
+func InFilter[V any](field string, values []V) bson.D {
+	if values == nil {
+		// A nil slice encodes as null, which $in rejects.
+		values = []V{}
+	}
+	return bson.D{{Key: field, Value: bson.D{{Key: "$in", Value: values}}}}
+}
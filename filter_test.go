@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestInFilter(t *testing.T) {
+	t.Run("BSON", func(t *testing.T) {
+		got, err := bson.MarshalExtJSON(InFilter("age", []int{30, 35}), false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := `{"age":{"$in":[30,35]}}`; string(got) != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+
+		got, err = bson.MarshalExtJSON(InFilter[string]("name", nil), false, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := `{"name":{"$in":[]}}`; string(got) != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("in_filter_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "in_filter_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Age: 30},
+		{ID: "2", Name: "Bob", Age: 35},
+		{ID: "3", Name: "Carol", Age: 40},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(users []testUser) []string {
+		var ids []string
+		for _, u := range users {
+			ids = append(ids, u.ID)
+		}
+		sort.Strings(ids)
+		return ids
+	}
+
+	t.Run("strings", func(t *testing.T) {
+		users, err := model.FindMany(ctx, InFilter("name", []string{"Alice", "Carol", "Dave"}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(users); len(got) != 2 || got[0] != "1" || got[1] != "3" {
+			t.Fatalf("expected [1 3], got %v", got)
+		}
+	})
+
+	t.Run("ints", func(t *testing.T) {
+		users, err := model.FindMany(ctx, InFilter("age", []int{35, 40}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ids(users); len(got) != 2 || got[0] != "2" || got[1] != "3" {
+			t.Fatalf("expected [2 3], got %v", got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		users, err := model.FindMany(ctx, InFilter[int]("age", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 0 {
+			t.Fatalf("expected no users, got %d", len(users))
+		}
+	})
+}
@@ -0,0 +1,590 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// FindLatest returns the document matching filter with the highest
+// sortField value, or ErrNotFound when nothing matches.
+func (m *mongoModel[T, C]) FindLatest(ctx context.Context, filter any, sortField string) (T, error) {
+	defer m.track("FindLatest")()
+
+	return m.findFirstBy(ctx, "FindLatest", filter, sortField, -1)
+}
+
+// FindOldest returns the document matching filter with the lowest
+// sortField value, or ErrNotFound when nothing matches.
+func (m *mongoModel[T, C]) FindOldest(ctx context.Context, filter any, sortField string) (T, error) {
+	defer m.track("FindOldest")()
+
+	return m.findFirstBy(ctx, "FindOldest", filter, sortField, 1)
+}
+
+// FindOneMasked returns the first document matching filter as a map that
+// contains only allowedFields, enforcing field-level access at query time.
+//
+// Fields may be dotted paths into embedded documents. _id is only
+// returned when it is explicitly allowed, and allowing no fields returns
+// an empty map for a matching document.
+func (m *mongoModel[T, C]) FindOneMasked(ctx context.Context, filter any, allowedFields ...string) (bson.M, error) {
+	defer m.track("FindOneMasked")()
+
+	projection := bson.D{}
+	allowsID := false
+	for _, field := range allowedFields {
+		if err := validateFieldPath(field); err != nil {
+			return nil, err
+		}
+		if field == "_id" {
+			allowsID = true
+		}
+		projection = append(projection, bson.E{Key: field, Value: 1})
+	}
+	if !allowsID {
+		projection = append(projection, bson.E{Key: "_id", Value: 0})
+	}
+	if len(allowedFields) == 0 {
+		// An exclusion-only projection would return every other field,
+		// so only fetch _id to check that a document matches.
+		projection = bson.D{{Key: "_id", Value: 1}}
+	}
+
+	var result bson.M
+	opts := options.FindOne().SetProjection(projection)
+	if comment := m.queryComment("FindOneMasked"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	if err := m.collection.FindOne(ctx, filter, opts).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(allowedFields) == 0 {
+		return bson.M{}, nil
+	}
+	return result, nil
+}
+
+// FindManyExtJSON retrieves all documents matching a filter written in
+// MongoDB Extended JSON, as emitted by tools like mongoexport or Compass,
+// e.g. {"_id": {"$oid": "5f1d7a3b2c8e4a0012345678"}}.
+//
+// Both canonical and relaxed Extended JSON are accepted. A filter that
+// does not parse into a document fails with ErrInvalidFilter.
+func (m *mongoModel[T, C]) FindManyExtJSON(
+	ctx context.Context,
+	filterJSON string,
+	opts ...*options.FindOptions,
+) ([]T, error) {
+	defer m.track("FindManyExtJSON")()
+
+	var filter bson.D
+	if err := bson.UnmarshalExtJSON([]byte(filterJSON), false, &filter); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	opts = withDefault(opts, m.queryComment("FindManyExtJSON"), func(o *options.FindOptions) *any { return &o.Comment })
+	return m.FindMany(ctx, filter, opts...)
+}
+
+// FindModifiedSince returns up to limit documents whose date field is
+// after since, oldest first, for incremental sync clients that poll for
+// changes. A limit of zero or less returns every match.
+//
+// Documents with equal field values are ordered by _id. The field value
+// of the last document is the checkpoint for the next call; documents
+// sharing that exact value but cut off by the limit are only picked up
+// when the writer's timestamps are unique.
+func (m *mongoModel[T, C]) FindModifiedSince(
+	ctx context.Context,
+	field string,
+	since time.Time,
+	limit int64,
+) ([]T, error) {
+	defer m.track("FindModifiedSince")()
+
+	if err := validateFieldPath(field); err != nil {
+		return nil, err
+	}
+
+	filter := bson.D{{Key: field, Value: bson.D{{Key: "$gt", Value: since}}}}
+	opts := options.Find().SetSort(bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts = opts.SetLimit(limit)
+	}
+	if comment := m.queryComment("FindModifiedSince"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCursor[T](ctx, cursor)
+}
+
+// FindOneLinearizable retrieves the document matching filter with the
+// linearizable read concern on the primary, so it reflects every write
+// acknowledged with the majority write concern before the read started,
+// even right after a failover.
+//
+// The primary confirms it is still primary with a majority of the
+// replica set before answering, which adds at least a round trip to the
+// secondaries to every call; keep it for single-document critical reads,
+// and give ctx a deadline since a partitioned primary blocks the read.
+// The filter should select a single document by a unique field.
+func (m *mongoModel[T, C]) FindOneLinearizable(ctx context.Context, filter any) (T, error) {
+	defer m.track("FindOneLinearizable")()
+
+	var result T
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	collection := m.collection.Clone(options.Collection().
+		SetReadConcern(readconcern.Linearizable()).
+		SetReadPreference(readpref.Primary()))
+	opts := options.FindOne()
+	if comment := m.queryComment("FindOneLinearizable"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	err := collection.FindOne(ctx, filter, opts).Decode(&result)
+	return result, err
+}
+
+// FindManyWithComputed retrieves the documents matching filter with the
+// computed fields added by a $addFields stage, decoded into C, e.g.
+// bson.D{{Key: "full_name", Value: bson.D{{Key: "$concat", Value: ...}}}}.
+//
+// It is meant for light computation on top of a find; reach for
+// Aggregate when the read needs grouping or more stages. An empty
+// computed document returns the matches unchanged.
+func (m *mongoModel[T, C]) FindManyWithComputed(ctx context.Context, filter any, computed bson.D) ([]C, error) {
+	defer m.track("FindManyWithComputed")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	pipeline := Pipeline().Match(filter)
+	if len(computed) > 0 {
+		pipeline = pipeline.Stage(bson.D{{Key: "$addFields", Value: computed}})
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("FindManyWithComputed"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline.Build(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	return decodeCursor[C](ctx, cursor)
+}
+
+// FindManyChannel streams the documents matching filter on a channel
+// buffered to bufSize, for pipeline-style processing without holding the
+// whole result in memory.
+//
+// The producer blocks while the buffer is full, so a slow consumer slows
+// the cursor down instead of piling documents up. Both channels are
+// closed once the cursor is drained or fails; the error channel receives
+// at most one error first. Cancelling ctx is how a consumer stops early:
+// the cursor is closed and no error is reported.
+func (m *mongoModel[T, C]) FindManyChannel(ctx context.Context, filter any, bufSize int) (<-chan T, <-chan error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	docs := make(chan T, max(bufSize, 0))
+	errs := make(chan error, 1)
+	opts := options.Find()
+	if comment := m.queryComment("FindManyChannel"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(docs)
+		defer m.track("FindManyChannel")()
+
+		cursor, err := m.collection.Find(ctx, filter, opts)
+		if err != nil {
+			if err := tailError(ctx, err); err != nil {
+				errs <- err
+			}
+			return
+		}
+		defer cursor.Close(context.WithoutCancel(ctx))
+
+		for cursor.Next(ctx) {
+			var v T
+			if err := cursor.Decode(&v); err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case docs <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if err := tailError(ctx, cursor.Err()); err != nil {
+			errs <- err
+		}
+	}()
+
+	return docs, errs
+}
+
+// FindManyGroupedBy retrieves the documents matching filter and groups
+// them by the key keyFn returns for each one, e.g. to build an in-memory
+// lookup table. Within a group, documents keep the FindMany order, and no
+// matches yields an empty map.
+func (m *mongoModel[T, C]) FindManyGroupedBy(
+	ctx context.Context,
+	filter any,
+	keyFn func(T) string,
+) (map[string][]T, error) {
+	defer m.track("FindManyGroupedBy")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := withDefault(nil, m.queryComment("FindManyGroupedBy"), func(o *options.FindOptions) *any { return &o.Comment })
+	docs, err := m.FindMany(ctx, filter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[string][]T)
+	for _, doc := range docs {
+		key := keyFn(doc)
+		groups[key] = append(groups[key], doc)
+	}
+	return groups, nil
+}
+
+// FindOrphans returns the documents whose localField references no
+// document of the from collection by foreignField, e.g. users pointing
+// at a deleted department, for referential integrity audits.
+//
+// Documents where localField is missing or null reference nothing and
+// are not orphans. When localField is an array, a document is an orphan
+// only if none of its references resolve.
+func (m *mongoModel[T, C]) FindOrphans(ctx context.Context, localField, from, foreignField string) ([]T, error) {
+	defer m.track("FindOrphans")()
+
+	for _, field := range []string{localField, foreignField} {
+		if err := validateFieldPath(field); err != nil {
+			return nil, err
+		}
+	}
+
+	const joined = "__orphan_refs"
+	pipeline := Pipeline().
+		Match(bson.D{{Key: localField, Value: bson.D{{Key: "$ne", Value: nil}}}}).
+		Stage(bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: from},
+			{Key: "localField", Value: localField},
+			{Key: "foreignField", Value: foreignField},
+			{Key: "as", Value: joined},
+		}}}).
+		Match(bson.D{{Key: joined, Value: bson.D{{Key: "$size", Value: 0}}}}).
+		Stage(bson.D{{Key: "$unset", Value: joined}}).
+		Build()
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("FindOrphans"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	return decodeCursor[T](ctx, cursor)
+}
+
+// defaultScanBatchSize is used by Scan when no batch size is given.
+const defaultScanBatchSize = 1000
+
+// Scan calls fn with every document of the collection in ascending _id
+// order, e.g. for consistent backups, fetching batchSize documents per
+// query and stopping at the first error fn returns.
+//
+// Each batch resumes after the last _id seen rather than skipping, so
+// documents are never visited twice and concurrent inserts or deletes
+// do not shift the remaining pages; documents inserted with a higher
+// _id during the scan are visited. Since range queries only compare
+// values of the same BSON type, every _id must share the type of the
+// first one.
+func (m *mongoModel[T, C]) Scan(ctx context.Context, batchSize int, fn func(T) error) error {
+	defer m.track("Scan")()
+
+	if batchSize <= 0 {
+		batchSize = defaultScanBatchSize
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(batchSize))
+	if comment := m.queryComment("Scan"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	filter := bson.D{}
+	for {
+		cursor, err := m.collection.Find(ctx, filter, opts)
+		if err != nil {
+			return err
+		}
+
+		var (
+			last bson.RawValue
+			seen int
+		)
+		for cursor.Next(ctx) {
+			var v T
+			if err := cursor.Decode(&v); err != nil {
+				_ = cursor.Close(ctx)
+				return err
+			}
+			if err := fn(v); err != nil {
+				_ = cursor.Close(ctx)
+				return err
+			}
+			// Copy the _id, as the cursor reuses its batch buffer.
+			id := cursor.Current.Lookup("_id")
+			last = bson.RawValue{Type: id.Type, Value: append([]byte(nil), id.Value...)}
+			seen++
+		}
+		err = cursor.Err()
+		_ = cursor.Close(ctx)
+		if err != nil {
+			return err
+		}
+		if seen < batchSize {
+			return nil
+		}
+		filter = bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: last}}}}
+	}
+}
+
+// Relation describes documents of another collection eagerly loaded by
+// FindOneWith, joined with a $lookup stage.
+type Relation struct {
+	// From is the collection holding the related documents.
+	From string
+
+	// LocalField is the field of the document holding the reference.
+	LocalField string
+
+	// ForeignField is the field of the related documents matched against
+	// LocalField, e.g. "_id" or "user_id".
+	ForeignField string
+
+	// As is the field of the result the related documents are stored in,
+	// as an array that is empty when nothing matches.
+	As string
+}
+
+// FindOneWith finds a single document with the documents of each
+// relation embedded, in one aggregation instead of a query per relation,
+// e.g. a user with their orders:
+//
+//	user, err := users.FindOneWith(ctx, bson.D{{Key: "_id", Value: id}}, []Relation{
+//		{From: "orders", LocalField: "_id", ForeignField: "user_id", As: "orders"},
+//	})
+//
+// The result is decoded into a bson.M, as the embedded relations change
+// its shape. It returns ErrNotFound when nothing matches.
+func (m *mongoModel[T, C]) FindOneWith(ctx context.Context, filter any, relations []Relation) (bson.M, error) {
+	defer m.track("FindOneWith")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	pipeline := Pipeline().
+		Match(filter).
+		Stage(bson.D{{Key: "$limit", Value: 1}})
+	for _, relation := range relations {
+		for _, field := range []string{relation.LocalField, relation.ForeignField, relation.As} {
+			if err := validateFieldPath(field); err != nil {
+				return nil, fmt.Errorf("relation %q: %w", relation.From, err)
+			}
+		}
+		pipeline.Stage(bson.D{{Key: "$lookup", Value: bson.D{
+			{Key: "from", Value: relation.From},
+			{Key: "localField", Value: relation.LocalField},
+			{Key: "foreignField", Value: relation.ForeignField},
+			{Key: "as", Value: relation.As},
+		}}})
+	}
+
+	opts := options.Aggregate()
+	if comment := m.queryComment("FindOneWith"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline.Build(), opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	results, err := decodeCursor[bson.M](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNotFound
+	}
+	return results[0], nil
+}
+
+// FindDuplicates reports the values of field shared by more than one
+// document, each mapped to the _id of the documents holding it, e.g. to
+// find duplicate emails before adding a unique index.
+//
+// String values are used as is for the map keys, other values in their
+// relaxed Extended JSON form, e.g. 42 or {"$oid": "..."}. Documents where
+// field is missing or null are ignored. The aggregation may use disk for
+// large collections.
+func (m *mongoModel[T, C]) FindDuplicates(ctx context.Context, field string) (map[string][]any, error) {
+	defer m.track("FindDuplicates")()
+
+	if err := validateFieldPath(field); err != nil {
+		return nil, err
+	}
+
+	pipeline := Pipeline().
+		Match(bson.D{{Key: field, Value: bson.D{{Key: "$ne", Value: nil}}}}).
+		Stage(bson.D{{Key: "$group", Value: bson.D{
+			{Key: "_id", Value: "$" + field},
+			{Key: "ids", Value: bson.D{{Key: "$push", Value: "$_id"}}},
+			{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}},
+		}}}).
+		Match(bson.D{{Key: "count", Value: bson.D{{Key: "$gt", Value: 1}}}}).
+		Build()
+	opts := options.Aggregate().SetAllowDiskUse(true)
+	if comment := m.queryComment("FindDuplicates"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
+	}
+	groups, err := decodeCursor[struct {
+		Value bson.RawValue `bson:"_id"`
+		IDs   []any         `bson:"ids"`
+	}](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make(map[string][]any, len(groups))
+	for _, group := range groups {
+		key, ok := group.Value.StringValueOK()
+		if !ok {
+			if key, err = relaxedExtJSON(group.Value); err != nil {
+				return nil, err
+			}
+		}
+		duplicates[key] = group.IDs
+	}
+	return duplicates, nil
+}
+
+// relaxedExtJSON renders a single value as relaxed Extended JSON.
+func relaxedExtJSON(v bson.RawValue) (string, error) {
+	wrapped, err := bson.MarshalExtJSON(bson.D{{Key: "v", Value: v}}, false, false)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(string(wrapped), `{"v":`), "}"), nil
+}
+
+// DiscriminatorField is the field FindManyPolymorphic reads to pick the
+// concrete type of each document.
+const DiscriminatorField = "type"
+
+// FindManyPolymorphic retrieves the documents matching filter from a
+// collection storing several document shapes, decoding each one into the
+// type registered for its DiscriminatorField value.
+//
+// Each registry factory must return a pointer to a new value, e.g.
+// func() any { return &Cat{} }; the results hold those pointers in
+// cursor order. A missing or unregistered discriminator value fails
+// with ErrUnknownDiscriminator.
+func (m *mongoModel[T, C]) FindManyPolymorphic(
+	ctx context.Context,
+	filter any,
+	registry map[string]func() any,
+) ([]any, error) {
+	defer m.track("FindManyPolymorphic")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := options.Find()
+	if comment := m.queryComment("FindManyPolymorphic"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := make([]any, 0)
+
+	for cursor.Next(ctx) {
+		kind, _ := cursor.Current.Lookup(DiscriminatorField).StringValueOK()
+		factory, ok := registry[kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownDiscriminator, kind)
+		}
+		item := factory()
+		if err := cursor.Decode(item); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (m *mongoModel[T, C]) findFirstBy(
+	ctx context.Context,
+	op string,
+	filter any,
+	sortField string,
+	direction int,
+) (T, error) {
+	var result T
+	if err := validateFieldPath(sortField); err != nil {
+		return result, err
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := options.FindOne().SetSort(bson.D{{Key: sortField, Value: direction}})
+	if comment := m.queryComment(op); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	if hint := m.autoHint(filter); hint != nil {
+		opts = opts.SetHint(hint)
+	}
+	if err := m.collection.FindOne(ctx, filter, opts).Decode(&result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
@@ -0,0 +1,624 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+type testEvent struct {
+	ID        string    `bson:"_id"`
+	Kind      string    `bson:"kind"`
+	CreatedAt time.Time `bson:"created_at"`
+}
+
+func TestFindLatestAndOldest(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("latest_events").Drop(ctx)
+
+	model := New[testEvent, testEvent](db, "latest_events")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, e := range []testEvent{
+		{ID: "b", Kind: "login", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: "a", Kind: "login", CreatedAt: base},
+		{ID: "c", Kind: "login", CreatedAt: base.Add(4 * time.Hour)},
+		{ID: "d", Kind: "logout", CreatedAt: base.Add(8 * time.Hour)},
+	} {
+		if err := model.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	logins := map[string]any{"kind": "login"}
+
+	latest, err := model.FindLatest(ctx, logins, "created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.ID != "c" {
+		t.Fatalf("expected c, got %s", latest.ID)
+	}
+
+	oldest, err := model.FindOldest(ctx, logins, "created_at")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if oldest.ID != "a" {
+		t.Fatalf("expected a, got %s", oldest.ID)
+	}
+
+	_, err = model.FindLatest(ctx, map[string]any{"kind": "missing"}, "created_at")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+type testSyncDoc struct {
+	ID        string    `bson:"_id"`
+	UpdatedAt time.Time `bson:"updated_at"`
+}
+
+func TestFindModifiedSince(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("sync_docs").Drop(ctx)
+
+	model := New[testSyncDoc, testSyncDoc](db, "sync_docs")
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, id := range []string{"c", "a", "e", "b", "d"} {
+		doc := testSyncDoc{ID: id, UpdatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := model.Create(ctx, doc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := func(docs []testSyncDoc) string {
+		var ids []string
+		for _, d := range docs {
+			ids = append(ids, d.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	checkpoint := base.Add(time.Minute)
+	docs, err := model.FindModifiedSince(ctx, "updated_at", checkpoint, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(docs); got != "e,b" {
+		t.Fatalf("expected e,b, got %s", got)
+	}
+
+	docs, err = model.FindModifiedSince(ctx, "updated_at", docs[len(docs)-1].UpdatedAt, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(docs); got != "d" {
+		t.Fatalf("expected d, got %s", got)
+	}
+}
+
+func TestFindOneMasked(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("masked_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "masked_users")
+	err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30, Position: "Dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := map[string]any{"_id": "1"}
+
+	doc, err := model.FindOneMasked(ctx, filter, "name", "position")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc["name"] != "Alice" || doc["position"] != "Dev" {
+		t.Fatalf("expected allowed fields, got %v", doc)
+	}
+	for _, field := range []string{"_id", "email", "age"} {
+		if _, ok := doc[field]; ok {
+			t.Fatalf("expected %s to be masked, got %v", field, doc)
+		}
+	}
+
+	doc, err = model.FindOneMasked(ctx, filter, "_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc) != 1 || doc["_id"] != "1" {
+		t.Fatalf("expected only _id, got %v", doc)
+	}
+
+	doc, err = model.FindOneMasked(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(doc) != 0 {
+		t.Fatalf("expected empty map, got %v", doc)
+	}
+
+	if _, err := model.FindOneMasked(ctx, map[string]any{"_id": "missing"}, "name"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+type testCircle struct {
+	ID     string  `bson:"_id"`
+	Type   string  `bson:"type"`
+	Radius float64 `bson:"radius"`
+}
+
+type testRect struct {
+	ID     string  `bson:"_id"`
+	Type   string  `bson:"type"`
+	Width  float64 `bson:"width"`
+	Height float64 `bson:"height"`
+}
+
+func TestFindManyPolymorphic(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("shapes").Drop(ctx)
+
+	shapes := db.Collection("shapes")
+	_, err := shapes.InsertMany(ctx, []any{
+		testCircle{ID: "1", Type: "circle", Radius: 2},
+		testRect{ID: "2", Type: "rect", Width: 3, Height: 4},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := New[map[string]any, map[string]any](db, "shapes")
+	registry := map[string]func() any{
+		"circle": func() any { return &testCircle{} },
+		"rect":   func() any { return &testRect{} },
+	}
+
+	results, err := model.FindManyPolymorphic(ctx, map[string]any{}, registry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+
+	for _, r := range results {
+		switch v := r.(type) {
+		case *testCircle:
+			if v.Radius != 2 {
+				t.Fatalf("unexpected circle %+v", v)
+			}
+		case *testRect:
+			if v.Width != 3 || v.Height != 4 {
+				t.Fatalf("unexpected rect %+v", v)
+			}
+		default:
+			t.Fatalf("unexpected type %T", r)
+		}
+	}
+
+	delete(registry, "rect")
+	if _, err := model.FindManyPolymorphic(ctx, map[string]any{}, registry); !errors.Is(err, ErrUnknownDiscriminator) {
+		t.Fatalf("expected ErrUnknownDiscriminator, got %v", err)
+	}
+}
+
+func TestFindManyExtJSON(t *testing.T) {
+	type doc struct {
+		ID   bson.ObjectID `bson:"_id"`
+		Name string        `bson:"name"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("extjson_docs").Drop(ctx)
+
+	model := New[doc, doc](db, "extjson_docs")
+	target := bson.NewObjectID()
+	for _, d := range []doc{
+		{ID: target, Name: "target"},
+		{ID: bson.NewObjectID(), Name: "other"},
+	} {
+		if err := model.Create(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	docs, err := model.FindManyExtJSON(ctx, `{"_id": {"$oid": "`+target.Hex()+`"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Name != "target" {
+		t.Fatalf("expected the target document, got %+v", docs)
+	}
+}
+
+func TestFindManyExtJSONInvalidFilter(t *testing.T) {
+	model := &mongoModel[testUser, testUser]{}
+	for _, invalid := range []string{`{"_id": `, `[1, 2]`, `{"_id": {"$oid": "nope"}}`} {
+		if _, err := model.FindManyExtJSON(context.Background(), invalid); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("expected ErrInvalidFilter for %s, got %v", invalid, err)
+		}
+	}
+}
+
+func TestFindManyWithComputed(t *testing.T) {
+	type withFullName struct {
+		FirstName string `bson:"first_name"`
+		FullName  string `bson:"full_name"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("computed_employees").Drop(ctx)
+
+	model := New[testEmployee, withFullName](db, "computed_employees")
+	for _, e := range []testEmployee{
+		{ID: "1", FirstName: "Ada", LastName: "Lovelace", Position: "Dev"},
+		{ID: "2", FirstName: "Alan", LastName: "Turing", Position: "QA"},
+	} {
+		if err := model.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	computed := bson.D{{Key: "full_name", Value: bson.D{
+		{Key: "$concat", Value: bson.A{"$first_name", " ", "$last_name"}},
+	}}}
+	results, err := model.FindManyWithComputed(ctx, bson.D{{Key: "position", Value: "Dev"}}, computed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %d", len(results))
+	}
+	if results[0].FullName != "Ada Lovelace" {
+		t.Fatalf("expected Ada Lovelace, got %q", results[0].FullName)
+	}
+}
+
+func TestFindManyChannel(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("channel_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "channel_users")
+	for i := range 20 {
+		if err := model.Create(ctx, testUser{ID: fmt.Sprintf("%02d", i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("drained", func(t *testing.T) {
+		docs, errs := model.FindManyChannel(ctx, nil, 4)
+		count := 0
+		for range docs {
+			count++
+		}
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+		if count != 20 {
+			t.Fatalf("expected 20 documents, got %d", count)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		streamCtx, cancel := context.WithCancel(ctx)
+		docs, errs := model.FindManyChannel(streamCtx, nil, 1)
+		for range 3 {
+			if _, ok := <-docs; !ok {
+				t.Fatal("expected a document before cancelling")
+			}
+		}
+		cancel()
+
+		// The producer must stop and close both channels instead of
+		// blocking forever on the full buffer.
+		done := make(chan struct{})
+		go func() {
+			for range docs {
+			}
+			if err := <-errs; err != nil {
+				t.Errorf("expected nil, got %v", err)
+			}
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("producer goroutine leaked after cancel")
+		}
+	})
+}
+
+func TestFindManyGroupedBy(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("grouped_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "grouped_users", WithDefaultSort(bson.D{{Key: "_id", Value: 1}}))
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "Dev"},
+		{ID: "2", Name: "Bob", Position: "QA"},
+		{ID: "3", Name: "Carol", Position: "Dev"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	groups, err := model.FindManyGroupedBy(ctx, nil, func(u testUser) string { return u.Position })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 groups, got %d", len(groups))
+	}
+	names := func(users []testUser) string {
+		var parts []string
+		for _, u := range users {
+			parts = append(parts, u.Name)
+		}
+		return strings.Join(parts, ",")
+	}
+	if got := names(groups["Dev"]); got != "Alice,Carol" {
+		t.Fatalf("expected Alice,Carol in Dev, got %s", got)
+	}
+	if got := names(groups["QA"]); got != "Bob" {
+		t.Fatalf("expected Bob in QA, got %s", got)
+	}
+}
+
+func TestFindOrphans(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("orphan_users").Drop(ctx)
+	_ = db.Collection("orphan_departments").Drop(ctx)
+
+	if _, err := db.Collection("orphan_departments").InsertOne(ctx, bson.D{{Key: "_id", Value: "Dev"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	model := New[testUser, testUser](db, "orphan_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "Dev"},
+		{ID: "2", Name: "Bob", Position: "Sales"},
+		{ID: "3", Name: "Carol", Position: "Dev"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.Collection("orphan_users").InsertOne(ctx, bson.D{{Key: "_id", Value: "4"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	orphans, err := model.FindOrphans(ctx, "position", "orphan_departments", "_id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphans) != 1 || orphans[0].Name != "Bob" {
+		t.Fatalf("expected only Bob, got %+v", orphans)
+	}
+}
+
+func TestFindOneWith(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("with_users").Drop(ctx)
+	_ = db.Collection("with_orders").Drop(ctx)
+
+	model := New[testUser, testUser](db, "with_users")
+	for _, u := range []testUser{{ID: "1", Name: "Alice"}, {ID: "2", Name: "Bob"}} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	orders := []any{
+		bson.D{{Key: "_id", Value: "o1"}, {Key: "user_id", Value: "1"}},
+		bson.D{{Key: "_id", Value: "o2"}, {Key: "user_id", Value: "1"}},
+		bson.D{{Key: "_id", Value: "o3"}, {Key: "user_id", Value: "2"}},
+	}
+	if _, err := db.Collection("with_orders").InsertMany(ctx, orders); err != nil {
+		t.Fatal(err)
+	}
+
+	relations := []Relation{{From: "with_orders", LocalField: "_id", ForeignField: "user_id", As: "orders"}}
+	user, err := model.FindOneWith(ctx, bson.D{{Key: "_id", Value: "1"}}, relations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user["name"] != "Alice" {
+		t.Fatalf("expected Alice, got %v", user)
+	}
+	loaded, ok := user["orders"].(bson.A)
+	if !ok {
+		t.Fatalf("expected an embedded orders array, got %v", user["orders"])
+	}
+	if len(loaded) != 2 {
+		t.Fatalf("expected 2 orders, got %d", len(loaded))
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := model.FindOneWith(ctx, bson.D{{Key: "_id", Value: "missing"}}, relations)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+
+	t.Run("invalid relation", func(t *testing.T) {
+		_, err := model.FindOneWith(ctx, bson.D{}, []Relation{{From: "with_orders", LocalField: "_id", ForeignField: "$user", As: "orders"}})
+		if err == nil {
+			t.Fatal("expected an error for an invalid field")
+		}
+	})
+}
+
+func TestFindOneLinearizable(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	requireReplicaSet(t, db)
+	_ = db.Collection("linearizable_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "linearizable_users", WithWriteTimeout(5*time.Second))
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "age", Value: 31}}}}
+	if err := model.UpdateOne(ctx, bson.D{{Key: "_id", Value: "1"}}, update); err != nil {
+		t.Fatal(err)
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	user, err := model.FindOneLinearizable(readCtx, bson.D{{Key: "_id", Value: "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Age != 31 {
+		t.Fatalf("expected the latest age 31, got %d", user.Age)
+	}
+}
+
+func TestScan(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("scan_users").Drop(ctx)
+
+	docs := make([]any, 0, 250)
+	for i := range 250 {
+		docs = append(docs, testUser{ID: fmt.Sprintf("%03d", i)})
+	}
+	if _, err := db.Collection("scan_users").InsertMany(ctx, docs); err != nil {
+		t.Fatal(err)
+	}
+	model := New[testUser, testUser](db, "scan_users")
+
+	visits := make(map[string]int)
+	var order []string
+	err := model.Scan(ctx, 50, func(u testUser) error {
+		visits[u.ID]++
+		order = append(order, u.ID)
+		// Concurrent writers keep appending documents with higher ids.
+		if len(order)%50 == 1 && len(order) < 250 {
+			return model.Create(ctx, testUser{ID: fmt.Sprintf("z%03d", len(order))})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := range 250 {
+		if id := fmt.Sprintf("%03d", i); visits[id] != 1 {
+			t.Fatalf("expected %s visited once, got %d", id, visits[id])
+		}
+	}
+	if len(order) != len(visits) || len(visits) != 255 {
+		t.Fatalf("expected 255 unique visits, got %d of %d", len(visits), len(order))
+	}
+	if !slices.IsSorted(order) {
+		t.Fatal("expected documents in _id order")
+	}
+
+	t.Run("page larger than the cursor batch", func(t *testing.T) {
+		// The first batch of a find holds 101 documents, so each page of
+		// 150 needs a getMore before its last _id is read.
+		var ids []string
+		err := model.Scan(ctx, 150, func(u testUser) error {
+			ids = append(ids, u.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 255 || !slices.IsSorted(ids) || len(slices.Compact(slices.Clone(ids))) != 255 {
+			t.Fatalf("expected 255 unique documents in _id order, got %d", len(ids))
+		}
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		errStop := errors.New("stop")
+		calls := 0
+		err := model.Scan(ctx, 10, func(testUser) error {
+			calls++
+			return errStop
+		})
+		if !errors.Is(err, errStop) || calls != 1 {
+			t.Fatalf("expected errStop after 1 call, got %v after %d", err, calls)
+		}
+	})
+}
+
+func TestFindDuplicates(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("duplicate_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "duplicate_users")
+	for _, u := range []testUser{
+		{ID: "1", Email: "alice@test.com", Age: 30},
+		{ID: "2", Email: "bob@test.com", Age: 30},
+		{ID: "3", Email: "alice@test.com", Age: 41},
+		{ID: "4", Email: "carol@test.com", Age: 52},
+		{ID: "5", Email: "alice@test.com", Age: 63},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	duplicates, err := model.FindDuplicates(ctx, "email")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(duplicates) != 1 {
+		t.Fatalf("expected 1 duplicate email, got %v", duplicates)
+	}
+	ids := duplicates["alice@test.com"]
+	got := make([]string, 0, len(ids))
+	for _, id := range ids {
+		got = append(got, id.(string))
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"1", "3", "5"}) {
+		t.Fatalf("expected ids [1 3 5], got %v", got)
+	}
+
+	t.Run("non-string values", func(t *testing.T) {
+		duplicates, err := model.FindDuplicates(ctx, "age")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(duplicates["30"]) != 2 {
+			t.Fatalf("expected 2 ids for age 30, got %v", duplicates)
+		}
+	})
+}
+
+func TestRelaxedExtJSON(t *testing.T) {
+	raw, err := bson.Marshal(bson.D{{Key: "n", Value: int32(30)}, {Key: "doc", Value: bson.D{{Key: "a", Value: 1.5}}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for field, expected := range map[string]string{"n": "30", "doc": `{"a":1.5}`} {
+		got, err := relaxedExtJSON(bson.Raw(raw).Lookup(field))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package mongodb
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// HintRule hints the index named Index for queries whose filter has
+// exactly the top-level Fields, in any order.
+type HintRule struct {
+	// Fields are the top-level filter fields the rule matches, e.g.
+	// "tenant_id" and "created_at".
+	Fields []string
+
+	// Index is the name of the index to hint.
+	Index string
+}
+
+// WithAutoHint makes FindOne, FindMany, FindLatest and FindOldest hint
+// the index of the first rule matching the filter, so call sites do not
+// each repeat the hint. Queries matching no rule, and calls setting a
+// hint of their own, are left to the query planner.
+//
+// The server fails a query hinting an index that does not exist, so keep
+// the rules in sync with the collection's indexes.
+func WithAutoHint(rules []HintRule) ModelOption {
+	return func(c *modelConfig) {
+		c.hintRules = rules
+	}
+}
+
+// autoHint returns the index name hinted for filter, or nil when no rule
+// matches it.
+func (m *mongoModel[T, C]) autoHint(filter any) any {
+	if len(m.config.hintRules) == 0 || filter == nil {
+		return nil
+	}
+	raw, err := bson.Marshal(filter)
+	if err != nil {
+		return nil
+	}
+	elems, err := bson.Raw(raw).Elements()
+	if err != nil {
+		return nil
+	}
+
+	fields := make([]string, 0, len(elems))
+	for _, elem := range elems {
+		fields = append(fields, elem.Key())
+	}
+	slices.Sort(fields)
+
+	for _, rule := range m.config.hintRules {
+		ruleFields := slices.Clone(rule.Fields)
+		slices.Sort(ruleFields)
+		if slices.Equal(fields, ruleFields) {
+			return rule.Index
+		}
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestAutoHint(t *testing.T) {
+	m := &mongoModel[testUser, testUser]{config: modelConfig{hintRules: []HintRule{
+		{Fields: []string{"position", "age"}, Index: "position_age"},
+		{Fields: []string{"email"}, Index: "email"},
+	}}}
+
+	cases := []struct {
+		name   string
+		filter any
+		want   any
+	}{
+		{"same fields in another order", bson.D{{Key: "age", Value: 30}, {Key: "position", Value: "Dev"}}, "position_age"},
+		{"map filter", map[string]any{"email": "alice@test.com"}, "email"},
+		{"extra field", bson.D{{Key: "email", Value: "a"}, {Key: "age", Value: 1}}, nil},
+		{"empty filter", bson.D{}, nil},
+		{"nil filter", nil, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := m.autoHint(c.filter); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestWithAutoHint(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu    sync.Mutex
+		hints []bson.RawValue
+	)
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if e.CommandName == "find" {
+				mu.Lock()
+				hints = append(hints, e.Command.Lookup("hint"))
+				mu.Unlock()
+			}
+		},
+	}
+	db := testDatabase(t, withCommandMonitor(monitor))
+	_ = db.Collection("hinted_users").Drop(ctx)
+
+	_, err := db.Collection("hinted_users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "position", Value: 1}, {Key: "age", Value: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := New[testUser, testUser](db, "hinted_users", WithAutoHint([]HintRule{
+		{Fields: []string{"position", "age"}, Index: "position_1_age_1"},
+	}))
+	if err := model.Create(ctx, testUser{ID: "1", Position: "Dev", Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := model.FindMany(ctx, bson.D{{Key: "position", Value: "Dev"}, {Key: "age", Value: 30}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(users))
+	}
+	if _, err := model.FindMany(ctx, bson.D{{Key: "position", Value: "Dev"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.FindLatest(ctx, bson.D{{Key: "age", Value: 30}, {Key: "position", Value: "Dev"}}, "age"); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(hints) != 3 {
+		t.Fatalf("expected 3 finds, got %d", len(hints))
+	}
+	if name, _ := hints[2].StringValueOK(); name != "position_1_age_1" {
+		t.Fatalf("expected FindLatest hinted with position_1_age_1, got %v", hints[2])
+	}
+	if name, _ := hints[0].StringValueOK(); name != "position_1_age_1" {
+		t.Fatalf("expected the matching query hinted with position_1_age_1, got %v", hints[0])
+	}
+	if hints[1].Type != 0 {
+		t.Fatalf("expected the other query not hinted, got %v", hints[1])
+	}
+}
@@ -0,0 +1,146 @@
+package mongodb
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// defaultImportBatchSize is used by ImportJSONL when no batch size is given.
+const defaultImportBatchSize = 1000
+
+// LineError reports an input line that could not be imported.
+type LineError struct {
+	// Line is the 1-based line number in the input.
+	Line int
+
+	// Err is the reason the line was rejected.
+	Err error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// ImportJSONL reads newline-delimited Extended JSON from r, decodes each
+// line into T and inserts the documents in batches of batchSize using
+// InsertMany. It returns the number of inserted documents.
+//
+// Blank lines are skipped. Malformed lines do not stop the import; they
+// are collected and returned as joined *LineError values once the input
+// is exhausted. A failed insert stops the import immediately.
+func (m *mongoModel[T, C]) ImportJSONL(ctx context.Context, r io.Reader, batchSize int) (int64, error) {
+	defer m.track("ImportJSONL")()
+
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+
+	var (
+		inserted int64
+		lineErrs []error
+		batch    = make([]T, 0, batchSize)
+	)
+	opts := options.InsertMany()
+	if comment := m.queryComment("ImportJSONL"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		wctx, cancel := m.writeContext(ctx)
+		defer cancel()
+		result, err := m.collection.InsertMany(wctx, batch, opts)
+		if result != nil {
+			inserted += int64(len(result.InsertedIDs))
+		}
+		if err != nil {
+			return writeConcernTimeout(err)
+		}
+		m.audit(ctx, "ImportJSONL", nil, batch)
+		batch = make([]T, 0, batchSize)
+		return nil
+	}
+
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return inserted, readErr
+		}
+
+		if data = bytes.TrimSpace(data); len(data) > 0 {
+			var doc T
+			if err := bson.UnmarshalExtJSON(data, false, &doc); err != nil {
+				lineErrs = append(lineErrs, &LineError{Line: line, Err: err})
+			} else if err := m.checkDocSize(doc); err != nil {
+				lineErrs = append(lineErrs, &LineError{Line: line, Err: err})
+			} else {
+				batch = append(batch, doc)
+			}
+		}
+
+		if len(batch) == batchSize {
+			if err := flush(); err != nil {
+				return inserted, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if err := flush(); err != nil {
+		return inserted, err
+	}
+	return inserted, errors.Join(lineErrs...)
+}
+
+// ExistingIDs returns the subset of ids that are already present in the
+// collection, so an import can skip or report them up front instead of
+// failing part way through on duplicate keys.
+//
+// Only _id is fetched. The values are returned as stored, in cursor
+// order, so numeric ids may come back as a different Go integer type
+// than the one passed in. No ids yields an empty slice.
+func (m *mongoModel[T, C]) ExistingIDs(ctx context.Context, ids []any) ([]any, error) {
+	defer m.track("ExistingIDs")()
+
+	existing := make([]any, 0)
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	opts := options.Find().SetProjection(bson.D{{Key: "_id", Value: 1}})
+	if comment := m.queryComment("ExistingIDs"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := decodeCursor[struct {
+		ID any `bson:"_id"`
+	}](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+	for _, doc := range docs {
+		existing = append(existing, doc.ID)
+	}
+	return existing, nil
+}
@@ -0,0 +1,90 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestImportJSONL(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("import_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "import_users")
+
+	input := strings.Join([]string{
+		`{"_id": "1", "name": "Alice", "email": "alice@test.com", "age": 30, "position": "Dev"}`,
+		`{"_id": "2", "name": "Bob", "age": `,
+		``,
+		`{"_id": "3", "name": "Carol", "email": "carol@test.com", "age": 40, "position": "QA"}`,
+		`{"_id": "4", "name": "Dave", "email": "dave@test.com", "age": 45, "position": "QA"}`,
+	}, "\n")
+
+	inserted, err := model.ImportJSONL(ctx, strings.NewReader(input), 2)
+	if inserted != 3 {
+		t.Fatalf("expected 3 inserted, got %d", inserted)
+	}
+
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) {
+		t.Fatalf("expected LineError, got %v", err)
+	}
+	if lineErr.Line != 2 {
+		t.Fatalf("expected malformed line 2, got %d", lineErr.Line)
+	}
+
+	users, err := model.FindMany(ctx, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 3 {
+		t.Fatalf("expected 3 users, got %d", len(users))
+	}
+
+	carol, err := model.FindOne(ctx, map[string]any{"_id": "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carol.Name != "Carol" || carol.Age != 40 {
+		t.Fatalf("unexpected user %+v", carol)
+	}
+}
+
+func TestExistingIDs(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("existing_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "existing_users")
+	for _, id := range []string{"1", "3", "5"} {
+		if err := model.Create(ctx, testUser{ID: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	existing, err := model.ExistingIDs(ctx, []any{"1", "2", "3", "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make([]string, 0, len(existing))
+	for _, id := range existing {
+		got = append(got, id.(string))
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"1", "3"}) {
+		t.Fatalf("expected [1 3], got %v", got)
+	}
+
+	t.Run("no ids", func(t *testing.T) {
+		existing, err := model.ExistingIDs(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if existing == nil || len(existing) != 0 {
+			t.Fatalf("expected an empty slice, got %v", existing)
+		}
+	})
+}
@@ -0,0 +1,448 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// EnsureUniqueIndex creates a unique index on keys unless one already
+// exists, and returns the index name.
+//
+// Creating an index that exists with different options fails on the
+// server, so existing indexes are checked first. This makes the call
+// safe to repeat, e.g. on every startup or migration run. An existing
+// non-unique index on the same keys is reported as an error instead of
+// being replaced.
+func (m *mongoModel[T, C]) EnsureUniqueIndex(ctx context.Context, keys bson.D) (string, error) {
+	defer m.track("EnsureUniqueIndex")()
+
+	specs, err := m.collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, spec := range specs {
+		same, err := sameIndexKeys(spec.KeysDocument, keys)
+		if err != nil {
+			return "", err
+		}
+		if !same {
+			continue
+		}
+		if spec.Unique == nil || !*spec.Unique {
+			return "", fmt.Errorf("index %q exists on the same keys but is not unique", spec.Name)
+		}
+		return spec.Name, nil
+	}
+
+	return m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true),
+	})
+}
+
+// CreateCollatedIndex creates an index on keys using collation for its
+// string comparisons, and returns the index name. With unique set and a
+// strength 1 or 2 collation this enforces case-insensitive uniqueness,
+// e.g. one account per email regardless of case:
+//
+//	model.CreateCollatedIndex(ctx,
+//		bson.D{{Key: "email", Value: 1}},
+//		&options.Collation{Locale: "en", Strength: 2},
+//		true,
+//	)
+//
+// Uniqueness is always enforced with the index collation, but queries
+// only use the index when they specify the same collation.
+func (m *mongoModel[T, C]) CreateCollatedIndex(
+	ctx context.Context,
+	keys bson.D,
+	collation *options.Collation,
+	unique bool,
+) (string, error) {
+	defer m.track("CreateCollatedIndex")()
+
+	opts := options.Index().SetCollation(collation)
+	if unique {
+		opts = opts.SetUnique(true)
+	}
+	return m.collection.Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys, Options: opts})
+}
+
+// RequireIndexes checks that an index exists on each of the required key
+// patterns, e.g. to fail startup instead of silently running collection
+// scans in production. Key order and direction must match exactly.
+//
+// When some are missing it returns an error wrapping ErrMissingIndexes
+// that lists their key patterns.
+func (m *mongoModel[T, C]) RequireIndexes(ctx context.Context, required []bson.D) error {
+	defer m.track("RequireIndexes")()
+
+	specs, err := m.collection.Indexes().ListSpecifications(ctx)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, keys := range required {
+		found := false
+		for _, spec := range specs {
+			same, err := sameIndexKeys(spec.KeysDocument, keys)
+			if err != nil {
+				return err
+			}
+			if same {
+				found = true
+				break
+			}
+		}
+		if !found {
+			pattern, err := bson.MarshalExtJSON(keys, false, false)
+			if err != nil {
+				return err
+			}
+			missing = append(missing, string(pattern))
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w on %s: %s", ErrMissingIndexes, m.Name, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// IsCovered reports whether a find with filter and projection is covered
+// by an index, i.e. answered from the index alone without fetching any
+// document (a PROJECTION_COVERED or index-only plan).
+//
+// The query is explained, not executed. A covered query needs a
+// projection that excludes _id unless _id is part of the index.
+func (m *mongoModel[T, C]) IsCovered(ctx context.Context, filter any, projection bson.D) (bool, error) {
+	defer m.track("IsCovered")()
+
+	stages, err := m.explainFindStages(ctx, filter, projection)
+	if err != nil {
+		return false, err
+	}
+	indexed := false
+	for _, stage := range stages {
+		switch stage {
+		case "FETCH", "COLLSCAN", "CLUSTERED_IXSCAN":
+			return false, nil
+		case "IXSCAN", "COUNT_SCAN", "DISTINCT_SCAN":
+			indexed = true
+		}
+	}
+	return indexed, nil
+}
+
+// explainFindStages explains a find and returns the stage names of its
+// winning plan, from the root down.
+func (m *mongoModel[T, C]) explainFindStages(ctx context.Context, filter any, projection bson.D) ([]string, error) {
+	if filter == nil {
+		filter = bson.D{}
+	}
+	find := bson.D{
+		{Key: "find", Value: m.Name},
+		{Key: "filter", Value: filter},
+	}
+	if len(projection) > 0 {
+		find = append(find, bson.E{Key: "projection", Value: projection})
+	}
+
+	raw, err := m.collection.Database().RunCommand(ctx, bson.D{
+		{Key: "explain", Value: find},
+		{Key: "verbosity", Value: "queryPlanner"},
+	}).Raw()
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := raw.LookupErr("queryPlanner", "winningPlan")
+	if err != nil {
+		return nil, fmt.Errorf("explain returned no winning plan: %w", err)
+	}
+	doc, ok := plan.DocumentOK()
+	if !ok {
+		return nil, fmt.Errorf("explain returned an invalid winning plan")
+	}
+	return planStages(doc), nil
+}
+
+// planStages walks an explain plan and collects its stage names. Plans
+// from the slot-based engine nest the classic plan under "queryPlan".
+func planStages(plan bson.Raw) []string {
+	var stages []string
+	if stage, ok := plan.Lookup("stage").StringValueOK(); ok {
+		stages = append(stages, stage)
+	}
+	for _, key := range []string{"queryPlan", "inputStage"} {
+		if child, ok := plan.Lookup(key).DocumentOK(); ok {
+			stages = append(stages, planStages(child)...)
+		}
+	}
+	if children, ok := plan.Lookup("inputStages").ArrayOK(); ok {
+		values, _ := children.Values()
+		for _, v := range values {
+			if child, ok := v.DocumentOK(); ok {
+				stages = append(stages, planStages(child)...)
+			}
+		}
+	}
+	return stages
+}
+
+// sameIndexKeys reports whether an index key document lists the same
+// fields, in the same order and direction, as keys. Numeric directions
+// are compared by value, so 1 and int64(1) are the same key.
+func sameIndexKeys(existing bson.Raw, keys bson.D) (bool, error) {
+	wanted, err := bson.Marshal(keys)
+	if err != nil {
+		return false, err
+	}
+
+	got, err := existing.Elements()
+	if err != nil {
+		return false, err
+	}
+	want, err := bson.Raw(wanted).Elements()
+	if err != nil {
+		return false, err
+	}
+	if len(got) != len(want) {
+		return false, nil
+	}
+
+	for i := range got {
+		if got[i].Key() != want[i].Key() {
+			return false, nil
+		}
+		g, w := got[i].Value(), want[i].Value()
+		if g.IsNumber() && w.IsNumber() {
+			if g.AsFloat64() != w.AsFloat64() {
+				return false, nil
+			}
+			continue
+		}
+		if !g.Equal(w) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// IndexSpec is a portable, JSON-serializable description of an index, as
+// produced by ExportIndexes and consumed by ImportIndexes, e.g. to keep
+// index definitions under version control.
+type IndexSpec struct {
+	// Name is the index name.
+	Name string `json:"name"`
+
+	// Keys are the indexed fields in index order.
+	Keys []IndexKey `json:"keys"`
+
+	// Unique rejects documents with duplicate key values.
+	Unique bool `json:"unique,omitempty"`
+
+	// Sparse skips documents that lack the indexed fields.
+	Sparse bool `json:"sparse,omitempty"`
+
+	// ExpireAfterSeconds makes the index a TTL index when set.
+	ExpireAfterSeconds *int32 `json:"expireAfterSeconds,omitempty"`
+
+	// PartialFilterExpression limits the index to matching documents, as
+	// relaxed Extended JSON.
+	PartialFilterExpression json.RawMessage `json:"partialFilterExpression,omitempty"`
+
+	// Collation sets the string comparison rules of the index.
+	Collation *options.Collation `json:"collation,omitempty"`
+}
+
+// IndexKey is an indexed field and its direction or type: 1 or -1 for
+// ascending or descending, or a string such as "2dsphere" or "hashed".
+type IndexKey struct {
+	Field string `json:"field"`
+	Value any    `json:"value"`
+}
+
+// ExportIndexes describes every index of the collection except the
+// mandatory _id index as an IndexSpec.
+//
+// The name, keys, unique, sparse, TTL, partial filter and collation
+// settings are exported; other options, such as hidden, are not. Text
+// indexes store their keys in an internal form and fail the export.
+func (m *mongoModel[T, C]) ExportIndexes(ctx context.Context) ([]IndexSpec, error) {
+	defer m.track("ExportIndexes")()
+
+	return exportIndexes(ctx, m.collection)
+}
+
+// exportIndexes describes the indexes of coll except the _id index.
+func exportIndexes(ctx context.Context, coll *mongo.Collection) ([]IndexSpec, error) {
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	specs := make([]IndexSpec, 0)
+	for cursor.Next(ctx) {
+		spec, err := indexSpecFromRaw(cursor.Current)
+		if err != nil {
+			return nil, err
+		}
+		if spec.Name != "_id_" {
+			specs = append(specs, spec)
+		}
+	}
+	return specs, cursor.Err()
+}
+
+// ImportIndexes creates the indexes described by specs, e.g. as exported
+// from another environment with ExportIndexes. Indexes that already exist
+// with the same definition are left as is, and a spec for the _id index
+// is skipped.
+func (m *mongoModel[T, C]) ImportIndexes(ctx context.Context, specs []IndexSpec) error {
+	defer m.track("ImportIndexes")()
+
+	models := make([]mongo.IndexModel, 0, len(specs))
+	for _, spec := range specs {
+		if spec.Name == "_id_" {
+			continue
+		}
+		model, err := spec.indexModel()
+		if err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+		models = append(models, model)
+	}
+	if len(models) == 0 {
+		return nil
+	}
+
+	_, err := m.collection.Indexes().CreateMany(ctx, models)
+	return err
+}
+
+// indexSpecFromRaw converts an index document returned by listIndexes.
+func indexSpecFromRaw(raw bson.Raw) (IndexSpec, error) {
+	var doc struct {
+		Name                    string   `bson:"name"`
+		Key                     bson.Raw `bson:"key"`
+		Unique                  bool     `bson:"unique"`
+		Sparse                  bool     `bson:"sparse"`
+		ExpireAfterSeconds      *int32   `bson:"expireAfterSeconds"`
+		PartialFilterExpression bson.Raw `bson:"partialFilterExpression"`
+		Collation               *struct {
+			Locale          string `bson:"locale"`
+			CaseLevel       bool   `bson:"caseLevel"`
+			CaseFirst       string `bson:"caseFirst"`
+			Strength        int    `bson:"strength"`
+			NumericOrdering bool   `bson:"numericOrdering"`
+			Alternate       string `bson:"alternate"`
+			MaxVariable     string `bson:"maxVariable"`
+			Normalization   bool   `bson:"normalization"`
+			Backwards       bool   `bson:"backwards"`
+		} `bson:"collation"`
+	}
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		return IndexSpec{}, err
+	}
+
+	spec := IndexSpec{
+		Name:               doc.Name,
+		Unique:             doc.Unique,
+		Sparse:             doc.Sparse,
+		ExpireAfterSeconds: doc.ExpireAfterSeconds,
+	}
+
+	keys, err := doc.Key.Elements()
+	if err != nil {
+		return IndexSpec{}, err
+	}
+	for _, key := range keys {
+		if key.Key() == "_fts" {
+			return IndexSpec{}, fmt.Errorf("index %q: text indexes are not supported", doc.Name)
+		}
+		var value any = key.Value().String()
+		if s, ok := key.Value().StringValueOK(); ok {
+			value = s
+		} else if n, ok := key.Value().AsFloat64OK(); ok {
+			value = n
+		}
+		spec.Keys = append(spec.Keys, IndexKey{Field: key.Key(), Value: value})
+	}
+
+	if doc.PartialFilterExpression != nil {
+		filter, err := bson.MarshalExtJSON(doc.PartialFilterExpression, false, false)
+		if err != nil {
+			return IndexSpec{}, err
+		}
+		spec.PartialFilterExpression = filter
+	}
+	if c := doc.Collation; c != nil {
+		spec.Collation = &options.Collation{
+			Locale:          c.Locale,
+			CaseLevel:       c.CaseLevel,
+			CaseFirst:       c.CaseFirst,
+			Strength:        c.Strength,
+			NumericOrdering: c.NumericOrdering,
+			Alternate:       c.Alternate,
+			MaxVariable:     c.MaxVariable,
+			Normalization:   c.Normalization,
+			Backwards:       c.Backwards,
+		}
+	}
+	return spec, nil
+}
+
+// indexModel converts the spec into an index model to create.
+func (spec IndexSpec) indexModel() (mongo.IndexModel, error) {
+	if len(spec.Keys) == 0 {
+		return mongo.IndexModel{}, errors.New("no keys")
+	}
+
+	keys := bson.D{}
+	for _, key := range spec.Keys {
+		value := key.Value
+		// Numbers decoded from JSON are float64, while the server
+		// reports integer directions.
+		if n, ok := value.(float64); ok && n == math.Trunc(n) {
+			value = int32(n)
+		}
+		keys = append(keys, bson.E{Key: key.Field, Value: value})
+	}
+
+	opts := options.Index()
+	if spec.Name != "" {
+		opts = opts.SetName(spec.Name)
+	}
+	if spec.Unique {
+		opts = opts.SetUnique(true)
+	}
+	if spec.Sparse {
+		opts = opts.SetSparse(true)
+	}
+	if spec.ExpireAfterSeconds != nil {
+		opts = opts.SetExpireAfterSeconds(*spec.ExpireAfterSeconds)
+	}
+	if len(spec.PartialFilterExpression) > 0 {
+		var filter bson.D
+		if err := bson.UnmarshalExtJSON(spec.PartialFilterExpression, false, &filter); err != nil {
+			return mongo.IndexModel{}, fmt.Errorf("invalid partial filter expression: %w", err)
+		}
+		opts = opts.SetPartialFilterExpression(filter)
+	}
+	if spec.Collation != nil {
+		opts = opts.SetCollation(spec.Collation)
+	}
+	return mongo.IndexModel{Keys: keys, Options: opts}, nil
+}
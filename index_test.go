@@ -0,0 +1,286 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestSameIndexKeys(t *testing.T) {
+	existing, err := bson.Marshal(bson.D{
+		{Key: "email", Value: int32(1)},
+		{Key: "age", Value: int32(-1)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		keys bson.D
+		same bool
+	}{
+		{bson.D{{Key: "email", Value: 1}, {Key: "age", Value: -1}}, true},
+		{bson.D{{Key: "email", Value: int64(1)}, {Key: "age", Value: -1.0}}, true},
+		{bson.D{{Key: "age", Value: -1}, {Key: "email", Value: 1}}, false},
+		{bson.D{{Key: "email", Value: 1}, {Key: "age", Value: 1}}, false},
+		{bson.D{{Key: "email", Value: 1}}, false},
+		{bson.D{{Key: "email", Value: "text"}, {Key: "age", Value: -1}}, false},
+	}
+	for _, c := range cases {
+		same, err := sameIndexKeys(existing, c.keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if same != c.same {
+			t.Fatalf("expected %v for %v, got %v", c.same, c.keys, same)
+		}
+	}
+}
+
+func TestEnsureUniqueIndex(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("unique_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "unique_users")
+	keys := bson.D{{Key: "email", Value: 1}, {Key: "position", Value: 1}}
+
+	first, err := model.EnsureUniqueIndex(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := model.EnsureUniqueIndex(ctx, keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatalf("expected same index name, got %q and %q", first, second)
+	}
+
+	specs, err := db.Collection("unique_users").Indexes().ListSpecifications(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The _id index plus the unique compound index.
+	if len(specs) != 2 {
+		t.Fatalf("expected 2 indexes, got %d", len(specs))
+	}
+}
+
+func TestPlanStages(t *testing.T) {
+	plan, err := bson.Marshal(bson.D{
+		{Key: "queryPlan", Value: bson.D{
+			{Key: "stage", Value: "PROJECTION_COVERED"},
+			{Key: "inputStage", Value: bson.D{
+				{Key: "stage", Value: "OR"},
+				{Key: "inputStages", Value: bson.A{
+					bson.D{{Key: "stage", Value: "IXSCAN"}},
+					bson.D{{Key: "stage", Value: "IXSCAN"}},
+				}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"PROJECTION_COVERED", "OR", "IXSCAN", "IXSCAN"}
+	if got := planStages(plan); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestIsCovered(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("covered_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "covered_users")
+	for _, u := range []testUser{
+		{ID: "1", Email: "alice@test.com", Position: "Dev"},
+		{ID: "2", Email: "bob@test.com", Position: "QA"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter := bson.D{{Key: "email", Value: "alice@test.com"}}
+	projection := bson.D{{Key: "email", Value: 1}, {Key: "_id", Value: 0}}
+
+	covered, err := model.IsCovered(ctx, filter, projection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if covered {
+		t.Fatal("expected query without index to be uncovered")
+	}
+
+	_, err = db.Collection("covered_users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "email", Value: 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	covered, err = model.IsCovered(ctx, filter, projection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !covered {
+		t.Fatal("expected query to be covered by the email index")
+	}
+
+	covered, err = model.IsCovered(ctx, filter, bson.D{{Key: "email", Value: 1}, {Key: "position", Value: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if covered {
+		t.Fatal("expected query projecting unindexed fields to be uncovered")
+	}
+}
+
+func TestRequireIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("required_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "required_users")
+	if _, err := model.EnsureUniqueIndex(ctx, bson.D{{Key: "email", Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := db.Collection("required_users").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "position", Value: 1}, {Key: "age", Value: -1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("present", func(t *testing.T) {
+		err := model.RequireIndexes(ctx, []bson.D{
+			{{Key: "_id", Value: 1}},
+			{{Key: "email", Value: 1}},
+			{{Key: "position", Value: 1}, {Key: "age", Value: -1}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		err := model.RequireIndexes(ctx, []bson.D{
+			{{Key: "email", Value: 1}},
+			{{Key: "name", Value: 1}},
+			{{Key: "position", Value: 1}, {Key: "age", Value: 1}},
+		})
+		if !errors.Is(err, ErrMissingIndexes) {
+			t.Fatalf("expected ErrMissingIndexes, got %v", err)
+		}
+		expected := `missing indexes on required_users: {"name":1}, {"position":1,"age":1}`
+		if err.Error() != expected {
+			t.Fatalf("expected %q, got %q", expected, err.Error())
+		}
+	})
+}
+
+func TestIndexSpecModel(t *testing.T) {
+	var spec IndexSpec
+	err := json.Unmarshal([]byte(`{
+		"name": "email_partial",
+		"keys": [{"field": "email", "value": 1}, {"field": "location", "value": "2dsphere"}],
+		"unique": true,
+		"partialFilterExpression": {"age": {"$gt": 18}}
+	}`), &spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model, err := spec.indexModel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := bson.D{{Key: "email", Value: int32(1)}, {Key: "location", Value: "2dsphere"}}
+	if !reflect.DeepEqual(model.Keys, expected) {
+		t.Fatalf("expected keys %v, got %v", expected, model.Keys)
+	}
+
+	if _, err := (IndexSpec{Name: "empty"}).indexModel(); err == nil {
+		t.Fatal("expected an error for a spec without keys")
+	}
+}
+
+func TestExportImportIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("indexes_source").Drop(ctx)
+	_ = db.Collection("indexes_target").Drop(ctx)
+
+	ttl := int32(3600)
+	_, err := db.Collection("indexes_source").Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetUnique(true).SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+		},
+		{Keys: bson.D{{Key: "position", Value: 1}, {Key: "age", Value: -1}}, Options: options.Index().SetSparse(true)},
+		{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(ttl)},
+		{
+			Keys: bson.D{{Key: "name", Value: 1}},
+			Options: options.Index().
+				SetName("adult_names").
+				SetPartialFilterExpression(bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: 18}}}}),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	source := New[testUser, testUser](db, "indexes_source")
+	exported, err := source.ExportIndexes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(exported) != 4 {
+		t.Fatalf("expected 4 indexes, got %d", len(exported))
+	}
+
+	// Round-trip through JSON, as when versioning the specs.
+	data, err := json.Marshal(exported)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var specs []IndexSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		t.Fatal(err)
+	}
+
+	target := New[testUser, testUser](db, "indexes_target")
+	if err := target.ImportIndexes(ctx, specs); err != nil {
+		t.Fatal(err)
+	}
+	// Importing again is a no-op.
+	if err := target.ImportIndexes(ctx, specs); err != nil {
+		t.Fatal(err)
+	}
+
+	imported, err := target.ExportIndexes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := func(specs []IndexSpec) map[string]string {
+		out := make(map[string]string)
+		for _, spec := range specs {
+			data, _ := json.Marshal(spec)
+			out[spec.Name] = string(data)
+		}
+		return out
+	}
+	if got, expected := byName(imported), byName(exported); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,95 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// locksCollection is the collection holding the locks of AcquireLock.
+const locksCollection = "locks"
+
+// AcquireLock tries to take the named lock for ttl, giving distributed
+// jobs (e.g. cron) mutual exclusion.
+//
+// Locks live in a dedicated "locks" collection of the model's database,
+// one document per name, so only a single holder exists at a time. The
+// lock is taken when it is free or its previous holder's ttl elapsed;
+// otherwise acquired is false. A TTL index removes expired locks.
+//
+// release frees the lock early. It only deletes the lock while it is
+// still owned by this holder and is safe to call more than once.
+func (m *mongoModel[T, C]) AcquireLock(
+	ctx context.Context,
+	name string,
+	ttl time.Duration,
+) (func(), bool, error) {
+	defer m.track("AcquireLock")()
+
+	locks := m.collection.Database().Collection(locksCollection)
+
+	_, err := locks.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	token, err := lockToken()
+	if err != nil {
+		return nil, false, err
+	}
+
+	updateOpts := options.UpdateOne().SetUpsert(true)
+	deleteOpts := options.DeleteOne()
+	if comment := m.queryComment("AcquireLock"); comment != nil {
+		updateOpts = updateOpts.SetComment(comment)
+		deleteOpts = deleteOpts.SetComment(comment)
+	}
+
+	now := time.Now()
+	_, err = locks.UpdateOne(
+		ctx,
+		bson.D{
+			{Key: "_id", Value: name},
+			{Key: "expires_at", Value: bson.D{{Key: "$lte", Value: now}}},
+		},
+		bson.D{{Key: "$set", Value: bson.D{
+			{Key: "owner", Value: token},
+			{Key: "expires_at", Value: now.Add(ttl)},
+		}}},
+		updateOpts,
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		// The lock exists and has not expired: the upsert tried to
+		// insert a second document with the same _id.
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	release := func() {
+		_, _ = locks.DeleteOne(
+			context.WithoutCancel(ctx),
+			bson.D{{Key: "_id", Value: name}, {Key: "owner", Value: token}},
+			deleteOpts,
+		)
+	}
+	return release, true, nil
+}
+
+// lockToken returns a random token identifying a lock holder.
+func lockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
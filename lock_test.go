@@ -0,0 +1,81 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection(locksCollection).Drop(ctx)
+
+	model := New[testUser, testUser](db, "lock_users")
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		acquired int
+		releases []func()
+	)
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, ok, err := model.AcquireLock(ctx, "nightly-report", time.Minute)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if ok {
+				mu.Lock()
+				acquired++
+				releases = append(releases, release)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if acquired != 1 {
+		t.Fatalf("expected exactly one holder, got %d", acquired)
+	}
+
+	_, ok, err := model.AcquireLock(ctx, "nightly-report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected held lock to be unavailable")
+	}
+
+	releases[0]()
+
+	release, ok, err := model.AcquireLock(ctx, "nightly-report", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected released lock to be acquired")
+	}
+	release()
+
+	t.Run("expired", func(t *testing.T) {
+		_, ok, err := model.AcquireLock(ctx, "short", 50*time.Millisecond)
+		if err != nil || !ok {
+			t.Fatalf("expected lock, got ok=%v err=%v", ok, err)
+		}
+		time.Sleep(100 * time.Millisecond)
+
+		release, ok, err := model.AcquireLock(ctx, "short", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected expired lock to be taken over")
+		}
+		release()
+	})
+}
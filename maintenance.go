@@ -0,0 +1,216 @@
+package mongodb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Validate runs the validate command on the collection and returns its
+// report, whose valid field tells whether the data and indexes are
+// consistent, with any problems listed under errors and warnings.
+//
+// A full validation also checks every document thoroughly, which is
+// slower and blocks writes to the collection for its duration, so it is
+// best kept to maintenance windows.
+func (m *mongoModel[T, C]) Validate(ctx context.Context, full bool) (bson.M, error) {
+	defer m.track("Validate")()
+
+	cmd := bson.D{{Key: "validate", Value: m.Name}, {Key: "full", Value: full}}
+
+	var report bson.M
+	if err := m.collection.Database().RunCommand(ctx, cmd).Decode(&report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// reindexTempField is the field appended to an index's keys to build its
+// temporary stand-in while ReindexCollection rebuilds it. No document is
+// expected to hold it, so the stand-in serves the same queries and
+// enforces the same uniqueness as the index it replaces.
+const reindexTempField = "__reindex"
+
+// reindexRejectedCodes are the server error codes of a reIndex command
+// refused outside a standalone server: replica set members reject it and
+// mongos does not know it.
+var reindexRejectedCodes = []int{
+	20, // IllegalOperation
+	59, // CommandNotFound
+}
+
+// ReindexCollection rebuilds every index of the collection, e.g. after a
+// bulk load left them fragmented.
+//
+// On a standalone server it runs the reIndex command, which holds an
+// exclusive lock on the collection, blocking all reads and writes, until
+// every index is rebuilt. Replica sets and sharded clusters reject
+// reIndex, so there every index except _id is rebuilt one at a time: it
+// is first covered by a temporary stand-in keyed on its fields plus an
+// absent field, then dropped, created again and the stand-in removed, so
+// queries and unique constraints keep an index throughout.
+//
+// That path is not online-safe either: every index is built twice, which
+// loads the server, and TTL indexes stop expiring documents while rebuilt.
+// Options that ExportIndexes does not carry, such as hidden, are lost,
+// and text indexes fail before anything is dropped. Stand-ins left by an
+// interrupted run are removed first when the index they cover exists.
+// When that index was lost too, it fails with ErrMissingIndexes before
+// changing anything; the index must be recreated by hand, e.g. with
+// ImportIndexes from an earlier export, and the stand-in is then removed
+// by the next run.
+func (m *mongoModel[T, C]) ReindexCollection(ctx context.Context) error {
+	defer m.track("ReindexCollection")()
+
+	err := m.collection.Database().RunCommand(ctx, bson.D{{Key: "reIndex", Value: m.Name}}).Err()
+	if !reindexRejected(err) {
+		return err
+	}
+
+	specs, err := m.ExportIndexes(ctx)
+	if err != nil {
+		return err
+	}
+	if specs, err = m.dropReindexStandIns(ctx, specs); err != nil {
+		return err
+	}
+
+	taken := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		taken[spec.Name] = true
+	}
+
+	indexes := m.collection.Indexes()
+	for _, spec := range specs {
+		temp := spec
+		temp.Name = spec.Name + "_reindex"
+		for n := 2; taken[temp.Name]; n++ {
+			temp.Name = fmt.Sprintf("%s_reindex%d", spec.Name, n)
+		}
+		temp.Keys = append(slices.Clone(spec.Keys), IndexKey{Field: reindexTempField, Value: 1})
+		// TTL indexes must have a single field.
+		temp.ExpireAfterSeconds = nil
+
+		tempModel, err := temp.indexModel()
+		if err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+		model, err := spec.indexModel()
+		if err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+
+		if _, err := indexes.CreateOne(ctx, tempModel); err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+		if err := indexes.DropOne(ctx, spec.Name); err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+		if _, err := indexes.CreateOne(ctx, model); err != nil {
+			return fmt.Errorf("index %q: %w", spec.Name, err)
+		}
+		if err := indexes.DropOne(ctx, temp.Name); err != nil {
+			return fmt.Errorf("index %q: %w", temp.Name, err)
+		}
+	}
+	return nil
+}
+
+// reindexRejected reports whether err is a reIndex command refused
+// because the server is not a standalone one.
+func reindexRejected(err error) bool {
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, code := range reindexRejectedCodes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// dropReindexStandIns removes the stand-ins an interrupted
+// ReindexCollection left next to the index they cover, and returns the
+// remaining specs. Stand-ins are recognized by their last key, so an
+// index merely named like one is kept.
+func (m *mongoModel[T, C]) dropReindexStandIns(ctx context.Context, specs []IndexSpec) ([]IndexSpec, error) {
+	var standIns, kept []IndexSpec
+	for _, spec := range specs {
+		if n := len(spec.Keys); n > 1 && spec.Keys[n-1].Field == reindexTempField {
+			standIns = append(standIns, spec)
+		} else {
+			kept = append(kept, spec)
+		}
+	}
+
+	var orphaned []string
+	for _, standIn := range standIns {
+		keys := standIn.Keys[:len(standIn.Keys)-1]
+		covered := slices.ContainsFunc(kept, func(spec IndexSpec) bool {
+			return slices.Equal(spec.Keys, keys)
+		})
+		if !covered {
+			orphaned = append(orphaned, standIn.Name)
+		}
+	}
+	if len(orphaned) > 0 {
+		return nil, fmt.Errorf(
+			"%w on %s: stand-ins %s replace lost indexes",
+			ErrMissingIndexes, m.Name, strings.Join(orphaned, ", "),
+		)
+	}
+
+	for _, standIn := range standIns {
+		if err := m.collection.Indexes().DropOne(ctx, standIn.Name); err != nil {
+			return nil, fmt.Errorf("index %q: %w", standIn.Name, err)
+		}
+	}
+	return kept, nil
+}
+
+// Checksum returns a SHA-256 digest, hex encoded, of the documents
+// matching filter, e.g. to verify a migration or a replica copied a
+// collection intact.
+//
+// Documents are streamed in _id order and their BSON bytes are hashed as
+// stored, so two collections holding the same documents produce the same
+// checksum, while any changed value, type or field order changes it. No
+// matches yields the digest of no data.
+func (m *mongoModel[T, C]) Checksum(ctx context.Context, filter any) (string, error) {
+	defer m.track("Checksum")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if comment := m.queryComment("Checksum"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return "", err
+	}
+	defer cursor.Close(ctx)
+
+	digest := sha256.New()
+	for cursor.Next(ctx) {
+		// Each document starts with its length, so the concatenation
+		// cannot be split differently.
+		digest.Write(cursor.Current)
+	}
+	if err := cursor.Err(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
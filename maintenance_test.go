@@ -0,0 +1,208 @@
+package mongodb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("validate_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "validate_users")
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, full := range []bool{false, true} {
+		report, err := model.Validate(ctx, full)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if report["valid"] != true {
+			t.Fatalf("expected a valid collection, got %v", report)
+		}
+		if report["nrecords"] != int32(1) {
+			t.Fatalf("expected 1 record, got %v", report["nrecords"])
+		}
+	}
+}
+
+func TestReindexCollection(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("reindex_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "reindex_users")
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.EnsureUniqueIndex(ctx, bson.D{{Key: "email", Value: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	ttl := int32(3600)
+	if err := model.ImportIndexes(ctx, []IndexSpec{
+		{Name: "age_ttl", Keys: []IndexKey{{Field: "age", Value: 1}}, ExpireAfterSeconds: &ttl},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	before, err := model.ExportIndexes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := model.ReindexCollection(ctx); err != nil {
+		t.Fatal(err)
+	}
+	after, err := model.ExportIndexes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := func(specs []IndexSpec) map[string]string {
+		out := make(map[string]string)
+		for _, spec := range specs {
+			data, _ := json.Marshal(spec)
+			out[spec.Name] = string(data)
+		}
+		return out
+	}
+	if got, expected := byName(after), byName(before); !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected the indexes %v to be rebuilt as is, got %v", expected, got)
+	}
+
+	t.Run("leftovers", func(t *testing.T) {
+		// Only the rebuild used outside standalone servers has stand-ins.
+		requireReplicaSet(t, db)
+
+		// An index named like a stand-in, which must survive the rebuild.
+		namesake := IndexSpec{Name: "age_ttl_reindex", Keys: []IndexKey{{Field: "name", Value: 1}}}
+		if err := model.ImportIndexes(ctx, []IndexSpec{namesake}); err != nil {
+			t.Fatal(err)
+		}
+		before, err := model.ExportIndexes(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A stand-in an interrupted run left next to the email index.
+		standIn := IndexSpec{
+			Name: "email_1_reindex",
+			Keys: []IndexKey{{Field: "email", Value: 1}, {Field: reindexTempField, Value: 1}},
+		}
+		if err := model.ImportIndexes(ctx, []IndexSpec{standIn}); err != nil {
+			t.Fatal(err)
+		}
+		if err := model.ReindexCollection(ctx); err != nil {
+			t.Fatal(err)
+		}
+		after, err := model.ExportIndexes(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, expected := byName(after), byName(before); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected the indexes %v without the stand-in, got %v", expected, got)
+		}
+
+		// A stand-in whose index was lost is not silently dropped.
+		orphan := IndexSpec{
+			Name: "position_1_reindex",
+			Keys: []IndexKey{{Field: "position", Value: 1}, {Field: reindexTempField, Value: 1}},
+		}
+		if err := model.ImportIndexes(ctx, []IndexSpec{orphan}); err != nil {
+			t.Fatal(err)
+		}
+		if err := model.ReindexCollection(ctx); !errors.Is(err, ErrMissingIndexes) {
+			t.Fatalf("expected ErrMissingIndexes, got %v", err)
+		}
+	})
+}
+
+func TestReindexRejected(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"standalone", nil, false},
+		{"replica set", mongo.CommandError{Code: 20}, true},
+		{"mongos", mongo.CommandError{Code: 59}, true},
+		{"other", mongo.CommandError{Code: 13}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := reindexRejected(c.err); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+
+	seed := func(name string, users []testUser) DefaultModel[testUser, testUser] {
+		_ = db.Collection(name).Drop(ctx)
+		model := New[testUser, testUser](db, name)
+		for _, u := range users {
+			if err := model.Create(ctx, u); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return model
+	}
+	users := []testUser{
+		{ID: "1", Name: "Alice", Age: 30},
+		{ID: "2", Name: "Bob", Age: 35},
+	}
+	// Insertion order differs, the _id order does not.
+	source := seed("checksum_source", users)
+	copied := seed("checksum_copy", []testUser{users[1], users[0]})
+
+	sum, err := source.Checksum(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	copySum, err := copied.Checksum(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != copySum {
+		t.Fatalf("expected identical checksums, got %s and %s", sum, copySum)
+	}
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "age", Value: 36}}}}
+	if err := copied.UpdateOne(ctx, bson.D{{Key: "_id", Value: "2"}}, update); err != nil {
+		t.Fatal(err)
+	}
+	changedSum, err := copied.Checksum(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changedSum == sum {
+		t.Fatal("expected a changed field to change the checksum")
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		unchanged := bson.D{{Key: "_id", Value: "1"}}
+		a, err := source.Checksum(ctx, unchanged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := copied.Checksum(ctx, unchanged)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b {
+			t.Fatalf("expected identical checksums for the unchanged document, got %s and %s", a, b)
+		}
+	})
+}
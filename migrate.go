@@ -0,0 +1,177 @@
+package mongodb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ConvertIDsToObjectID migrates documents whose _id is a hex string to an
+// ObjectID _id with the same value. Each document is reinserted under the
+// new _id and the old one is removed, so it returns how many were converted.
+//
+// On a replica set or sharded cluster each document is moved in its own
+// transaction, or in the session ctx carries; on a standalone server the
+// insert and delete run back to back, so an interrupted run may leave
+// both versions of a document. A rerun removes the string version when
+// the ObjectID one holds the same fields; when their contents differ,
+// both are left in place and the returned ErrIDConversionConflict lists
+// their _ids for reconciling by hand, while the other documents are still
+// converted. String _ids that are not valid ObjectID hex are left
+// untouched.
+func (m *mongoModel[T, C]) ConvertIDsToObjectID(ctx context.Context) (int64, error) {
+	defer m.track("ConvertIDsToObjectID")()
+
+	transactions, err := m.ownTransaction(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	findOpts := options.Find()
+	findOneOpts := options.FindOne()
+	insertOpts := options.InsertOne()
+	deleteOpts := options.DeleteOne()
+	if comment := m.queryComment("ConvertIDsToObjectID"); comment != nil {
+		findOpts = findOpts.SetComment(comment)
+		findOneOpts = findOneOpts.SetComment(comment)
+		insertOpts = insertOpts.SetComment(comment)
+		deleteOpts = deleteOpts.SetComment(comment)
+	}
+
+	filter := bson.D{{Key: "_id", Value: bson.D{{Key: "$type", Value: "string"}}}}
+	cursor, err := m.collection.Find(ctx, filter, findOpts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var (
+		converted int64
+		conflicts []string
+	)
+	for cursor.Next(ctx) {
+		oldID := cursor.Current.Lookup("_id").StringValue()
+		newID, err := bson.ObjectIDFromHex(oldID)
+		if err != nil {
+			continue
+		}
+
+		current := cursor.Current
+		elems, err := current.Elements()
+		if err != nil {
+			return converted, err
+		}
+		doc := bson.D{{Key: "_id", Value: newID}}
+		for _, elem := range elems {
+			if elem.Key() != "_id" {
+				doc = append(doc, bson.E{Key: elem.Key(), Value: elem.Value()})
+			}
+		}
+
+		move := func(ctx context.Context) error {
+			wctx, cancel := m.writeContext(ctx)
+			defer cancel()
+			existing, err := m.collection.FindOne(wctx, bson.D{{Key: "_id", Value: newID}}, findOneOpts).Raw()
+			switch {
+			case errors.Is(err, mongo.ErrNoDocuments):
+				_, err = m.collection.InsertOne(wctx, doc, insertOpts)
+			case err == nil:
+				var same bool
+				if same, err = sameFields(existing, current); err == nil && !same {
+					err = ErrIDConversionConflict
+				}
+			}
+			if err != nil {
+				return err
+			}
+			_, err = m.collection.DeleteOne(wctx, bson.D{{Key: "_id", Value: oldID}}, deleteOpts)
+			return err
+		}
+		if transactions {
+			err = NewDatabase(m.collection.Database()).WithTransaction(ctx, func(tx TransactionContext) error {
+				return move(tx)
+			})
+		} else {
+			err = move(ctx)
+		}
+		if errors.Is(err, ErrIDConversionConflict) {
+			conflicts = append(conflicts, oldID)
+			continue
+		}
+		if err != nil {
+			return converted, err
+		}
+		m.audit(ctx, "ConvertIDsToObjectID", bson.D{{Key: "_id", Value: oldID}}, doc)
+		converted++
+	}
+	if err := cursor.Err(); err != nil {
+		return converted, err
+	}
+	if len(conflicts) > 0 {
+		return converted, fmt.Errorf("%w: %s", ErrIDConversionConflict, strings.Join(conflicts, ", "))
+	}
+	return converted, nil
+}
+
+// sameFields reports whether a and b hold the same fields with the same
+// values in the same order, ignoring _id.
+func sameFields(a, b bson.Raw) (bool, error) {
+	aElems, err := a.Elements()
+	if err != nil {
+		return false, err
+	}
+	bElems, err := b.Elements()
+	if err != nil {
+		return false, err
+	}
+	withoutID := func(elems []bson.RawElement) []bson.RawElement {
+		kept := elems[:0]
+		for _, elem := range elems {
+			if elem.Key() != "_id" {
+				kept = append(kept, elem)
+			}
+		}
+		return kept
+	}
+	aElems, bElems = withoutID(aElems), withoutID(bElems)
+	if len(aElems) != len(bElems) {
+		return false, nil
+	}
+	for i := range aElems {
+		if !bytes.Equal(aElems[i], bElems[i]) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// supportsTransactions reports whether the server is a replica set member
+// or a mongos, the deployments that support multi-document transactions.
+func (m *mongoModel[T, C]) supportsTransactions(ctx context.Context) (bool, error) {
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	err := m.collection.Database().RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello)
+	if err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// ownTransaction reports whether an operation should run its steps in a
+// transaction of its own. When ctx already carries a session the steps
+// run in it instead, and the hello probe is skipped since the server
+// rejects it inside a transaction.
+func (m *mongoModel[T, C]) ownTransaction(ctx context.Context) (bool, error) {
+	if mongo.SessionFromContext(ctx) != nil {
+		return false, nil
+	}
+	return m.supportsTransactions(ctx)
+}
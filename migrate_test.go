@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestConvertIDsToObjectID(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("legacy_ids").Drop(ctx)
+
+	var audited int
+	model := New[bson.M, bson.M](db, "legacy_ids", WithAuditSink(func(_ context.Context, op string, _, _ any) {
+		if op == "ConvertIDsToObjectID" {
+			audited++
+		}
+	}))
+	ids := []string{
+		bson.NewObjectID().Hex(),
+		bson.NewObjectID().Hex(),
+		bson.NewObjectID().Hex(),
+	}
+	for i, id := range ids {
+		if err := model.Create(ctx, bson.M{"_id": id, "n": i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := model.Create(ctx, bson.M{"_id": "not-an-oid", "n": 9}); err != nil {
+		t.Fatal(err)
+	}
+	// An interrupted run left the first document under both _ids, and a
+	// later write reached only the string version of the second one.
+	first, _ := bson.ObjectIDFromHex(ids[0])
+	if err := model.Create(ctx, bson.M{"_id": first, "n": 0}); err != nil {
+		t.Fatal(err)
+	}
+	second, _ := bson.ObjectIDFromHex(ids[1])
+	if err := model.Create(ctx, bson.M{"_id": second, "n": 99}); err != nil {
+		t.Fatal(err)
+	}
+
+	converted, err := model.ConvertIDsToObjectID(ctx)
+	if !errors.Is(err, ErrIDConversionConflict) || !strings.Contains(err.Error(), ids[1]) {
+		t.Fatalf("expected ErrIDConversionConflict for %s, got %v", ids[1], err)
+	}
+	if converted != 2 {
+		t.Fatalf("expected 2 converted, got %d", converted)
+	}
+	if audited != 2 {
+		t.Fatalf("expected 2 audited conversions, got %d", audited)
+	}
+
+	for i, n := range []int{0, 99, 2} {
+		oid, _ := bson.ObjectIDFromHex(ids[i])
+		doc, err := model.FindOne(ctx, bson.M{"_id": oid})
+		if err != nil {
+			t.Fatalf("expected ObjectID %s, got %v", ids[i], err)
+		}
+		if got, _ := doc["n"].(int32); int(got) != n {
+			t.Fatalf("expected n %d, got %v", n, doc["n"])
+		}
+	}
+
+	remaining, err := model.FindMany(ctx, bson.M{"_id": bson.M{"$type": "string"}}, &options.FindOptions{Sort: bson.M{"n": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remaining) != 2 || remaining[0]["_id"] != ids[1] || remaining[1]["_id"] != "not-an-oid" {
+		t.Fatalf("expected the conflicting and non-convertible _ids left, got %v", remaining)
+	}
+
+	t.Run("rerun", func(t *testing.T) {
+		converted, err := model.ConvertIDsToObjectID(ctx)
+		if !errors.Is(err, ErrIDConversionConflict) {
+			t.Fatalf("expected ErrIDConversionConflict, got %v", err)
+		}
+		if converted != 0 {
+			t.Fatalf("expected nothing left to convert, got %d", converted)
+		}
+		all, err := model.FindMany(ctx, bson.M{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) != 5 {
+			t.Fatalf("expected 5 documents, got %d", len(all))
+		}
+	})
+}
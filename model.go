@@ -3,10 +3,15 @@ package mongodb
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"time"
 
+	"go.mongodb.org/mongo-driver/v2/bson"
 	"go.mongodb.org/mongo-driver/v2/mongo"
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"golang.org/x/sync/singleflight"
 )
 
 // mongoModel is a concrete MongoDB-backed implementation of Model.
@@ -20,22 +25,237 @@ type mongoModel[T, C any] struct {
 
 	// collection is the underlying MongoDB collection instance.
 	collection *mongo.Collection
+
+	// config holds the optional settings given to New.
+	config modelConfig
+
+	// findOneGroup deduplicates concurrent identical FindOne calls.
+	// It is nil unless WithSingleflight is set.
+	findOneGroup *singleflight.Group
 }
 
-// mongodb is a type alias that binds mongoModel to the generic Model interface.
+// mongodb binds mongoModel to the generic Model interface and adds
+// the operations that only make sense for MongoDB.
 //
 // This improves readability by exposing a domain-friendly type
 // while keeping the implementation details private.
-type mongodb[T, C any] Model[
-	T,
-	C,
-	any,
-	*options.FindOneOptions,
-	*options.FindOptions,
-	*options.UpdateOneOptions,
-	*options.UpdateManyOptions,
-	mongo.Pipeline,
-]
+type mongodb[T, C any] interface {
+	Model[
+		T,
+		C,
+		any,
+		*options.FindOneOptions,
+		*options.FindOptions,
+		*options.UpdateOneOptions,
+		*options.UpdateManyOptions,
+		*options.DeleteOneOptions,
+		*options.DeleteManyOptions,
+		mongo.Pipeline,
+	]
+
+	// IsCapped reports whether the collection is capped, along with its
+	// maximum size in bytes and maximum number of documents.
+	IsCapped(ctx context.Context) (capped bool, maxSize int64, maxDocs int64, err error)
+
+	// Sample returns up to n randomly selected documents.
+	Sample(ctx context.Context, n int64) ([]T, error)
+
+	// UpdateArrayElement updates the array elements matched by arrayFilters
+	// in a single document.
+	UpdateArrayElement(ctx context.Context, filter any, update any, arrayFilters []any) (*mongo.UpdateResult, error)
+
+	// CreateOrGet inserts a document or returns the existing one on a
+	// duplicate-key error.
+	CreateOrGet(ctx context.Context, doc T, filter any) (T, bool, error)
+
+	// CountCovered counts the documents matching filter using the hinted index.
+	CountCovered(ctx context.Context, filter any, hint string) (int64, error)
+
+	// Query starts a chainable query over the collection.
+	Query() *Query[T]
+
+	// AggregateInto executes an aggregation pipeline and decodes the results
+	// into dest.
+	AggregateInto(ctx context.Context, pipeline mongo.Pipeline, dest *[]C) error
+
+	// SetNested sets the embedded field addressed by a dotted path in a
+	// single document.
+	SetNested(ctx context.Context, filter any, path string, value any) (*mongo.UpdateResult, error)
+
+	// RenameField renames a field across all documents and returns the
+	// number of modified documents.
+	RenameField(ctx context.Context, oldName, newName string) (int64, error)
+
+	// FindLatest returns the matching document with the highest sortField value.
+	FindLatest(ctx context.Context, filter any, sortField string) (T, error)
+
+	// FindOldest returns the matching document with the lowest sortField value.
+	FindOldest(ctx context.Context, filter any, sortField string) (T, error)
+
+	// UpdateWithDiff updates a single document and returns it as it was
+	// before and after the update.
+	UpdateWithDiff(ctx context.Context, filter any, update any) (before, after T, err error)
+
+	// FindOneMasked returns a single document containing only the allowed fields.
+	FindOneMasked(ctx context.Context, filter any, allowedFields ...string) (bson.M, error)
+
+	// AcquireLock tries to take a named distributed lock for ttl.
+	AcquireLock(ctx context.Context, name string, ttl time.Duration) (release func(), acquired bool, err error)
+
+	// ExportJSONL streams matching documents to w as newline-delimited JSON.
+	ExportJSONL(ctx context.Context, filter any, w io.Writer) (int64, error)
+
+	// ImportJSONL inserts newline-delimited JSON documents read from r in batches.
+	ImportJSONL(ctx context.Context, r io.Reader, batchSize int) (int64, error)
+
+	// DistinctGroups returns the distinct combinations of the given fields.
+	DistinctGroups(ctx context.Context, fields []string, filter any) ([]bson.M, error)
+
+	// UpdateOneWithPipeline updates a single document using an aggregation pipeline.
+	UpdateOneWithPipeline(ctx context.Context, filter any, pipeline mongo.Pipeline, opts ...*options.UpdateOneOptions) (*mongo.UpdateResult, error)
+
+	// EnsureUniqueIndex creates a unique index on keys unless it already exists.
+	EnsureUniqueIndex(ctx context.Context, keys bson.D) (string, error)
+
+	// AggregateTo executes a pipeline ending in $merge or $out.
+	AggregateTo(ctx context.Context, pipeline mongo.Pipeline) error
+
+	// CountByDate counts matching documents per date bucket of dateField.
+	CountByDate(ctx context.Context, dateField string, granularity string, filter any) (map[string]int64, error)
+
+	// FindManyPolymorphic decodes each matching document into the type
+	// registered for its discriminator value.
+	FindManyPolymorphic(ctx context.Context, filter any, registry map[string]func() any) ([]any, error)
+
+	// NextSequence atomically increments and returns the named sequence.
+	NextSequence(ctx context.Context, name string) (int64, error)
+
+	// CreateReturningID inserts a new document and returns its _id.
+	CreateReturningID(ctx context.Context, v T) (any, error)
+
+	// ExportCSV streams the given columns of matching documents to w as CSV.
+	ExportCSV(ctx context.Context, filter any, columns []string, w io.Writer) (int64, error)
+
+	// Claim atomically updates and returns a single matching document.
+	Claim(ctx context.Context, filter any, claimUpdate any, sort ...any) (T, error)
+
+	// IsCovered reports whether a find is answered from an index alone.
+	IsCovered(ctx context.Context, filter any, projection bson.D) (bool, error)
+
+	// CountMissingField counts the documents that do not have field.
+	CountMissingField(ctx context.Context, field string) (int64, error)
+
+	// WatchChannel delivers change stream events on a channel until ctx ends.
+	WatchChannel(ctx context.Context, pipeline mongo.Pipeline) (<-chan ChangeEvent[T], <-chan error)
+
+	// DeleteExpired deletes the documents whose date field is before olderThan.
+	DeleteExpired(ctx context.Context, field string, olderThan time.Time) (int64, error)
+
+	// FindManyExtJSON retrieves all documents matching an Extended JSON filter.
+	FindManyExtJSON(ctx context.Context, filterJSON string, opts ...*options.FindOptions) ([]T, error)
+
+	// Append inserts a new entry at the end of a capped collection.
+	Append(ctx context.Context, v T) error
+
+	// Tail follows a capped collection, calling fn for every entry.
+	Tail(ctx context.Context, fn func(T) error) error
+
+	// ConvertIDsToObjectID migrates hex string _ids to ObjectIDs.
+	ConvertIDsToObjectID(ctx context.Context) (converted int64, err error)
+
+	// UpsertReturning upserts a document and returns the resulting version.
+	UpsertReturning(ctx context.Context, filter any, update any) (T, error)
+
+	// ValidateAgainstSchema checks a document against a JSON schema client-side.
+	ValidateAgainstSchema(doc T, schema bson.M) error
+
+	// ApplyMergePatch applies a JSON Merge Patch and returns the updated document.
+	ApplyMergePatch(ctx context.Context, filter any, patch []byte) (T, error)
+
+	// AggregateToJSON streams aggregation results to w as a JSON array.
+	AggregateToJSON(ctx context.Context, pipeline mongo.Pipeline, w io.Writer) (int64, error)
+
+	// CreateCollatedIndex creates an index comparing strings with collation.
+	CreateCollatedIndex(ctx context.Context, keys bson.D, collation *options.Collation, unique bool) (string, error)
+
+	// Stats returns the operation statistics recorded with WithStats.
+	Stats() OperationStats
+
+	// SumDecimal returns the exact decimal sum of field over matching documents.
+	SumDecimal(ctx context.Context, field string, filter any) (bson.Decimal128, error)
+
+	// RequireIndexes fails when an index on any of the key patterns is missing.
+	RequireIndexes(ctx context.Context, required []bson.D) error
+
+	// FindModifiedSince returns documents whose date field is after since, oldest first.
+	FindModifiedSince(ctx context.Context, field string, since time.Time, limit int64) ([]T, error)
+
+	// ExportIndexes describes the collection's indexes as portable specs.
+	ExportIndexes(ctx context.Context) ([]IndexSpec, error)
+
+	// ImportIndexes creates the indexes described by specs.
+	ImportIndexes(ctx context.Context, specs []IndexSpec) error
+
+	// CountByRange counts the documents whose field falls in each boundary range.
+	CountByRange(ctx context.Context, field string, boundaries []any) ([]int64, error)
+
+	// CompareAndSwap sets field to newValue only while it still holds expected.
+	CompareAndSwap(ctx context.Context, filter any, field string, expected, newValue any) (bool, error)
+
+	// Validate checks the collection's integrity and returns the report.
+	Validate(ctx context.Context, full bool) (bson.M, error)
+
+	// FindManyWithComputed retrieves documents with $addFields computed fields.
+	FindManyWithComputed(ctx context.Context, filter any, computed bson.D) ([]C, error)
+
+	// FindManyChannel streams matching documents on a buffered channel.
+	FindManyChannel(ctx context.Context, filter any, bufSize int) (<-chan T, <-chan error)
+
+	// ExistingIDs returns the subset of ids already present in the collection.
+	ExistingIDs(ctx context.Context, ids []any) ([]any, error)
+
+	// ReindexCollection rebuilds every index of the collection.
+	ReindexCollection(ctx context.Context) error
+
+	// FindManyGroupedBy retrieves matching documents grouped by a key.
+	FindManyGroupedBy(ctx context.Context, filter any, keyFn func(T) string) (map[string][]T, error)
+
+	// RotateEncryption re-encrypts fields from oldKey to newKey.
+	RotateEncryption(ctx context.Context, oldKey, newKey []byte, fields []string) (int64, error)
+
+	// EstimateCardinality estimates the distinct values of field from a sample.
+	EstimateCardinality(ctx context.Context, field string, sampleSize int64) (int64, error)
+
+	// FindOrphans returns documents referencing no document of another collection.
+	FindOrphans(ctx context.Context, localField, from, foreignField string) ([]T, error)
+
+	// FindOneLinearizable retrieves a document with linearizable read concern.
+	FindOneLinearizable(ctx context.Context, filter any) (T, error)
+
+	// AddToSet adds values to an array field of the matching documents.
+	AddToSet(ctx context.Context, filter any, field string, values ...any) (int64, error)
+
+	// Mutate applies fn to a document and saves it with a version check.
+	Mutate(ctx context.Context, filter any, fn func(*T) error) (T, error)
+
+	// Scan calls fn with every document in _id order using keyset pagination.
+	Scan(ctx context.Context, batchSize int, fn func(T) error) error
+
+	// Checksum returns a digest of the matching documents in _id order.
+	Checksum(ctx context.Context, filter any) (string, error)
+
+	// FindDuplicates maps the values of field shared by several documents to their _id.
+	FindDuplicates(ctx context.Context, field string) (map[string][]any, error)
+
+	// PreviewUpdate returns matching documents as they would look after update.
+	PreviewUpdate(ctx context.Context, filter any, update any, limit int) ([]T, error)
+
+	// UpdateManyResult updates all matching documents and returns the update result.
+	UpdateManyResult(ctx context.Context, filter any, update any, opts ...*options.UpdateManyOptions) (*mongo.UpdateResult, error)
+
+	// FindOneWith returns a single document with the documents of each relation embedded.
+	FindOneWith(ctx context.Context, filter any, relations []Relation) (bson.M, error)
+}
 
 // DefaultModel is the default MongoDB model type alias.
 type DefaultModel[T, C any] = mongodb[T, C]
@@ -44,12 +264,30 @@ type DefaultModel[T, C any] = mongodb[T, C]
 //
 // A single collection instance is reused, which is cheaper than
 // resolving the collection on every operation.
-func New[T, C any](db *mongo.Database, name string) DefaultModel[T, C] {
-	collection := db.Collection(name)
-	return &mongoModel[T, C]{
+func New[T, C any](db *mongo.Database, name string, opts ...ModelOption) DefaultModel[T, C] {
+	var config modelConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	collOpts := options.Collection()
+	if config.bsonOptions != nil {
+		collOpts = collOpts.SetBSONOptions(config.bsonOptions)
+	}
+	if config.readPreference != nil {
+		collOpts = collOpts.SetReadPreference(config.readPreference)
+	}
+
+	collection := db.Collection(name, collOpts)
+	m := &mongoModel[T, C]{
 		Name:       name,
 		collection: collection,
+		config:     config,
 	}
+	if config.singleflight {
+		m.findOneGroup = &singleflight.Group{}
+	}
+	return m
 }
 
 // FindOne retrieves a single document that matches the given filter.
@@ -61,11 +299,34 @@ func (m *mongoModel[T, C]) FindOne(
 	filter any,
 	opts ...*options.FindOneOptions,
 ) (T, error) {
-	var result T
-	if err := m.collection.FindOne(ctx, filter, BuildFindOneOptions(opts...)).Decode(&result); err != nil {
-		return result, err
+	defer m.track("FindOne")()
+
+	// Reads in a session must not be shared with other callers.
+	if m.findOneGroup != nil && len(opts) == 0 && mongo.SessionFromContext(ctx) == nil {
+		if key, err := bson.MarshalExtJSON(filter, true, false); err == nil {
+			v, err, _ := m.findOneGroup.Do(string(key), func() (any, error) {
+				return m.findOne(ctx, filter)
+			})
+			result, _ := v.(T)
+			return result, err
+		}
 	}
-	return result, nil
+	return m.findOne(ctx, filter, opts...)
+}
+
+func (m *mongoModel[T, C]) findOne(
+	ctx context.Context,
+	filter any,
+	opts ...*options.FindOneOptions,
+) (T, error) {
+	opts = withDefault(opts, m.queryComment("FindOne"), func(o *options.FindOneOptions) *any { return &o.Comment })
+	opts = withDefault(opts, m.autoHint(filter), func(o *options.FindOneOptions) *any { return &o.Hint })
+
+	var result T
+	err := m.retryRead(ctx, func() error {
+		return m.collection.FindOne(ctx, filter, BuildFindOneOptions(opts...)).Decode(&result)
+	})
+	return result, err
 }
 
 // FindMany retrieves all documents that match the given filter.
@@ -74,32 +335,32 @@ func (m *mongoModel[T, C]) FindMany(
 	filter any,
 	opts ...*options.FindOptions,
 ) ([]T, error) {
-	cursor, err := m.collection.Find(ctx, filter, BuildFindManyOptions(opts...))
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
+	defer m.track("FindMany")()
 
-	results := make([]T, 0)
+	opts = m.withDefaultSort(opts)
+	opts = withDefault(opts, m.queryComment("FindMany"), func(o *options.FindOptions) *any { return &o.Comment })
+	opts = withDefault(opts, m.autoHint(filter), func(o *options.FindOptions) *any { return &o.Hint })
 
-	for cursor.Next(ctx) {
-		var item T
-		if err := cursor.Decode(&item); err != nil {
-			return nil, err
+	var results []T
+	err := m.retryRead(ctx, func() error {
+		cursor, err := m.collection.Find(ctx, filter, BuildFindManyOptions(opts...))
+		if err != nil {
+			return err
 		}
-		results = append(results, item)
-	}
-
-	if err := cursor.Err(); err != nil {
+		results, err = decodeCursor[T](ctx, cursor)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
-
 	return results, nil
 }
 
 // Create inserts a new document into the collection.
 func (m *mongoModel[T, C]) Create(ctx context.Context, v T) error {
-	_, err := m.collection.InsertOne(ctx, v)
+	defer m.track("Create")()
+
+	_, err := m.insertOne(ctx, "Create", v)
 	return err
 }
 
@@ -110,7 +371,17 @@ func (m *mongoModel[T, C]) UpdateOne(
 	update any,
 	opts ...*options.UpdateOneOptions,
 ) error {
-	_, err := m.collection.UpdateOne(ctx, filter, update, BuildUpdateOneOptions(opts...))
+	defer m.track("UpdateOne")()
+
+	opts = withDefault(opts, m.queryComment("UpdateOne"), func(o *options.UpdateOneOptions) *any { return &o.Comment })
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	_, err := m.collection.UpdateOne(wctx, filter, update, BuildUpdateOneOptions(opts...))
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return err
+	}
+	m.audit(ctx, "UpdateOne", filter, update)
 	return err
 }
 
@@ -121,19 +392,71 @@ func (m *mongoModel[T, C]) UpdateMany(
 	update any,
 	opts ...*options.UpdateManyOptions,
 ) error {
-	_, err := m.collection.UpdateMany(ctx, filter, update, BuildUpdateManyOptions(opts...))
+	_, err := m.UpdateManyResult(ctx, filter, update, opts...)
 	return err
 }
 
+// UpdateManyResult updates all documents that match the given filter,
+// like UpdateMany, and returns the driver's result with the matched,
+// modified and upserted counts. Use UpsertedIDs to read the _id of an
+// upserted document.
+func (m *mongoModel[T, C]) UpdateManyResult(
+	ctx context.Context,
+	filter any,
+	update any,
+	opts ...*options.UpdateManyOptions,
+) (*mongo.UpdateResult, error) {
+	defer m.track("UpdateMany")()
+
+	opts = withDefault(opts, m.queryComment("UpdateMany"), func(o *options.UpdateManyOptions) *any { return &o.Comment })
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateMany(wctx, filter, update, BuildUpdateManyOptions(opts...))
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return nil, err
+	}
+	m.audit(ctx, "UpdateMany", filter, update)
+	return result, err
+}
+
 // DeleteOne removes a single document that matches the given filter.
-func (m *mongoModel[T, C]) DeleteOne(ctx context.Context, filter any) error {
-	_, err := m.collection.DeleteOne(ctx, filter)
+func (m *mongoModel[T, C]) DeleteOne(
+	ctx context.Context,
+	filter any,
+	opts ...*options.DeleteOneOptions,
+) error {
+	defer m.track("DeleteOne")()
+
+	opts = withDefault(opts, m.queryComment("DeleteOne"), func(o *options.DeleteOneOptions) *any { return &o.Comment })
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	_, err := m.collection.DeleteOne(wctx, filter, BuildDeleteOneOptions(opts...))
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return err
+	}
+	m.audit(ctx, "DeleteOne", filter, nil)
 	return err
 }
 
 // DeleteMany removes all documents that match the given filter.
-func (m *mongoModel[T, C]) DeleteMany(ctx context.Context, filter any) error {
-	_, err := m.collection.DeleteMany(ctx, filter)
+func (m *mongoModel[T, C]) DeleteMany(
+	ctx context.Context,
+	filter any,
+	opts ...*options.DeleteManyOptions,
+) error {
+	defer m.track("DeleteMany")()
+
+	opts = withDefault(opts, m.queryComment("DeleteMany"), func(o *options.DeleteManyOptions) *any { return &o.Comment })
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	_, err := m.collection.DeleteMany(wctx, filter, BuildDeleteManyOptions(opts...))
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return err
+	}
+	m.audit(ctx, "DeleteMany", filter, nil)
 	return err
 }
 
@@ -142,7 +465,13 @@ func (m *mongoModel[T, C]) Aggregate(
 	ctx context.Context,
 	pipeline mongo.Pipeline,
 ) ([]C, error) {
-	cursor, err := m.collection.Aggregate(ctx, pipeline)
+	defer m.track("Aggregate")()
+
+	aggOpts := options.Aggregate()
+	if comment := m.queryComment("Aggregate"); comment != nil {
+		aggOpts = aggOpts.SetComment(comment)
+	}
+	cursor, err := m.collection.Aggregate(ctx, pipeline, aggOpts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute aggregation: %w", err)
 	}
@@ -169,11 +498,59 @@ func (m *mongoModel[T, C]) Aggregate(
 	return results, nil
 }
 
+// Count returns the number of documents matching filter without loading
+// them. A nil or empty filter counts the whole collection.
+func (m *mongoModel[T, C]) Count(ctx context.Context, filter any) (int64, error) {
+	defer m.track("Count")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+	countOpts := options.Count()
+	if comment := m.queryComment("Count"); comment != nil {
+		countOpts = countOpts.SetComment(comment)
+	}
+
+	var count int64
+	err := m.retryRead(ctx, func() (err error) {
+		count, err = m.collection.CountDocuments(ctx, filter, countOpts)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// EstimatedCount returns the number of documents in the collection from
+// its metadata instead of scanning it, which stays fast on huge
+// collections. The value may be off after an unclean shutdown or while
+// orphaned documents exist on a sharded cluster; use Count when an exact
+// number is needed.
+func (m *mongoModel[T, C]) EstimatedCount(ctx context.Context) (int64, error) {
+	defer m.track("EstimatedCount")()
+
+	countOpts := options.EstimatedDocumentCount()
+	if comment := m.queryComment("EstimatedCount"); comment != nil {
+		countOpts = countOpts.SetComment(comment)
+	}
+
+	var count int64
+	err := m.retryRead(ctx, func() (err error) {
+		count, err = m.collection.EstimatedDocumentCount(ctx, countOpts)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // Model defines a generic interface for database operations.
 //
 // Generics provide compile-time safety and remove the need for
 // interface{} casting, which improves readability and performance.
-type Model[T, C, D, FO, FMO, UO, UM, P any] interface {
+type Model[T, C, D, FO, FMO, UO, UM, DO, DM, P any] interface {
 
 	// FindOne finds a single document that matches the filter.
 	FindOne(ctx context.Context, filter D, options ...FO) (T, error)
@@ -191,11 +568,17 @@ type Model[T, C, D, FO, FMO, UO, UM, P any] interface {
 	UpdateMany(ctx context.Context, filter D, data D, options ...UM) error
 
 	// DeleteOne deletes a single document that matches the filter.
-	DeleteOne(ctx context.Context, filter D) error
+	DeleteOne(ctx context.Context, filter D, options ...DO) error
 
 	// DeleteMany deletes all documents that match the filter.
-	DeleteMany(ctx context.Context, filter D) error
+	DeleteMany(ctx context.Context, filter D, options ...DM) error
 
 	// Aggregate executes an aggregation pipeline and returns custom results.
 	Aggregate(ctx context.Context, pipeline P) ([]C, error)
+
+	// Count returns the number of documents that match the filter.
+	Count(ctx context.Context, filter D) (int64, error)
+
+	// EstimatedCount returns the approximate number of documents in the collection.
+	EstimatedCount(ctx context.Context) (int64, error)
 }
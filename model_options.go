@@ -0,0 +1,174 @@
+package mongodb
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// ModelOption configures optional behavior of a model created by New.
+type ModelOption func(*modelConfig)
+
+// modelConfig holds the optional settings collected from ModelOption values.
+type modelConfig struct {
+	// singleflight deduplicates concurrent identical FindOne calls.
+	singleflight bool
+
+	// auditSink receives every successful write.
+	auditSink AuditSink
+
+	// idGenerator produces _id values for documents inserted without one.
+	idGenerator func() string
+
+	// bsonOptions controls how the collection encodes and decodes documents.
+	bsonOptions *options.BSONOptions
+
+	// stats records the count and latency of the core operations.
+	stats *statsRecorder
+
+	// defaultSort orders FindMany results when the caller sets no sort.
+	defaultSort bson.D
+
+	// readRetries is how many times a failed retryable read is retried.
+	readRetries int
+
+	// retryableError classifies read errors for readRetries.
+	retryableError RetryableErrorFunc
+
+	// writeTimeout bounds each write, including the wait for replication.
+	writeTimeout time.Duration
+
+	// readPreference routes the model's reads, overriding the database's.
+	readPreference *readpref.ReadPref
+
+	// maxDocSize is the largest encoded document inserts accept, in bytes.
+	maxDocSize int
+
+	// queryTags are attached as a comment to the core operations.
+	queryTags map[string]string
+
+	// hintRules pick the index hinted for a find by its filter fields.
+	hintRules []HintRule
+}
+
+// WithSingleflight makes concurrent FindOne calls with the same filter
+// share a single in-flight query instead of each hitting the database.
+//
+// This protects hot keys from a thundering herd. Calls passing options,
+// and calls inside a session such as a transaction, are never shared.
+// The shared query runs with the context of the call that started it,
+// and every caller receives the same decoded value, so reference fields
+// (maps, slices, pointers) in T must be treated as read-only.
+func WithSingleflight() ModelOption {
+	return func(c *modelConfig) {
+		c.singleflight = true
+	}
+}
+
+// WithIDGenerator makes inserts generate the document _id with fn, e.g. a
+// ULID or UUID, when the document has no _id or an empty string one.
+//
+// The document is marshaled to BSON and the generated _id is added to the
+// encoded copy, so T needs no setter; use CreateReturningID to learn the
+// generated value. Documents that already carry an _id are left as is.
+func WithIDGenerator(fn func() string) ModelOption {
+	return func(c *modelConfig) {
+		c.idGenerator = fn
+	}
+}
+
+// WithBSONOptions sets the BSON encoding and decoding behavior of the
+// model's collection, e.g. UseJSONStructTags to fall back to json tags,
+// or AllowTruncatingDoubles to decode doubles into integer fields.
+//
+// The options apply to every read and write of the model, including
+// FindOne and FindMany decoding.
+func WithBSONOptions(opts *options.BSONOptions) ModelOption {
+	return func(c *modelConfig) {
+		c.bsonOptions = opts
+	}
+}
+
+// WithDefaultSort makes FindMany return documents in sort order when the
+// call sets no sort of its own, e.g. bson.D{{Key: "_id", Value: 1}}
+// instead of the unspecified natural order. An explicit sort in the
+// FindOptions always overrides the default.
+func WithDefaultSort(sort bson.D) ModelOption {
+	return func(c *modelConfig) {
+		c.defaultSort = sort
+	}
+}
+
+// WithAnalyticsRouting sends every read of the model to the secondaries
+// tagged tagKey: tagValue, e.g. "nodeType": "ANALYTICS" on Atlas, so
+// heavy reads stay away from the members serving the application. Writes
+// always go to the primary.
+//
+// Reads fail with a server selection error when no tagged secondary is
+// available; they do not fall back to other members.
+func WithAnalyticsRouting(tagKey, tagValue string) ModelOption {
+	return func(c *modelConfig) {
+		c.readPreference = readpref.Secondary(readpref.WithTags(tagKey, tagValue))
+	}
+}
+
+// WithMaxDocSize makes inserts encode each document and reject it with
+// ErrDocumentTooLarge, reporting its actual size, when it is larger than
+// bytes, before anything is sent to the server. A limit below the 16MB
+// BSON maximum catches runaway documents early with a clear error.
+//
+// It applies to Create, Append, CreateReturningID and ImportJSONL, which
+// reports an oversized line as a *LineError.
+func WithMaxDocSize(bytes int) ModelOption {
+	return func(c *modelConfig) {
+		c.maxDocSize = bytes
+	}
+}
+
+// checkDocSize returns ErrDocumentTooLarge when doc encodes to more than
+// the configured maximum size.
+func (m *mongoModel[T, C]) checkDocSize(doc any) error {
+	if m.config.maxDocSize <= 0 {
+		return nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if len(raw) > m.config.maxDocSize {
+		return fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrDocumentTooLarge, len(raw), m.config.maxDocSize)
+	}
+	return nil
+}
+
+// withDefaultSort returns opts with the default sort applied, unless no
+// default is configured or opts already sets a sort. The caller's options
+// are not modified.
+func (m *mongoModel[T, C]) withDefaultSort(opts []*options.FindOptions) []*options.FindOptions {
+	if m.config.defaultSort == nil {
+		return opts
+	}
+	return withDefault(opts, m.config.defaultSort, func(o *options.FindOptions) *any { return &o.Sort })
+}
+
+// withDefault returns opts with value set through field, unless value is
+// nil or any of opts already sets the field. The default is prepended as
+// its own options value, so the caller's options are not modified and
+// still take precedence when merged.
+func withDefault[O any](opts []*O, value any, field func(*O) *any) []*O {
+	if value == nil {
+		return opts
+	}
+	for _, o := range opts {
+		if o != nil && *field(o) != nil {
+			return opts
+		}
+	}
+
+	var defaults O
+	*field(&defaults) = value
+	return append([]*O{&defaults}, opts...)
+}
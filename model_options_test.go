@@ -0,0 +1,335 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// withCommandMonitor is a test ConnectorOption that records started commands.
+func withCommandMonitor(monitor *event.CommandMonitor) ConnectorOption {
+	return func(c *DatabaseConnector) {
+		c.ClientOptions = options.Client().ApplyURI(c.URI).SetMonitor(monitor)
+	}
+}
+
+func TestWithSingleflight(t *testing.T) {
+	ctx := context.Background()
+
+	var finds atomic.Int64
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if e.CommandName == "find" {
+				finds.Add(1)
+			}
+		},
+	}
+	db := testDatabase(t, withCommandMonitor(monitor))
+	_ = db.Collection("singleflight_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "singleflight_users", WithSingleflight())
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The $where clause slows the query down so every goroutine joins
+	// the same in-flight call.
+	filter := bson.D{
+		{Key: "_id", Value: "1"},
+		{Key: "$where", Value: "sleep(200) || true"},
+	}
+
+	finds.Store(0)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			user, err := model.FindOne(ctx, filter)
+			if err == nil && user.Name != "Alice" {
+				t.Errorf("unexpected user %+v", user)
+			}
+			errs <- err
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := finds.Load(); n != 1 {
+		t.Fatalf("expected 1 find command, got %d", n)
+	}
+}
+
+func TestWithBSONOptions(t *testing.T) {
+	type jsonTagged struct {
+		ID       string `json:"_id"`
+		FullName string `json:"full_name"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("bson_options").Drop(ctx)
+
+	_, err := db.Collection("bson_options").InsertOne(ctx, bson.D{
+		{Key: "_id", Value: "1"},
+		{Key: "full_name", Value: "Ada Lovelace"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := bson.D{{Key: "_id", Value: "1"}}
+
+	plain := New[jsonTagged, jsonTagged](db, "bson_options")
+	doc, err := plain.FindOne(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.FullName != "" {
+		t.Fatalf("expected json tags to be ignored by default, got %q", doc.FullName)
+	}
+
+	tagged := New[jsonTagged, jsonTagged](
+		db,
+		"bson_options",
+		WithBSONOptions(&options.BSONOptions{UseJSONStructTags: true}),
+	)
+	doc, err = tagged.FindOne(ctx, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.FullName != "Ada Lovelace" {
+		t.Fatalf("expected json tags to be used, got %q", doc.FullName)
+	}
+
+	docs, err := tagged.FindMany(ctx, bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].ID != "1" {
+		t.Fatalf("expected FindMany to use json tags, got %+v", docs)
+	}
+}
+
+func TestWithDefaultSort(t *testing.T) {
+	byID := bson.D{{Key: "_id", Value: 1}}
+
+	t.Run("options", func(t *testing.T) {
+		var config modelConfig
+		WithDefaultSort(byID)(&config)
+		model := &mongoModel[testUser, testUser]{config: config}
+
+		opts := model.withDefaultSort(nil)
+		if len(opts) != 1 || !reflect.DeepEqual(opts[0].Sort, byID) {
+			t.Fatalf("expected default sort, got %+v", opts)
+		}
+
+		limit := int64(2)
+		caller := &options.FindOptions{Limit: &limit}
+		merged := listOptions(t, BuildFindManyOptions(model.withDefaultSort([]*options.FindOptions{caller})...))
+		if !reflect.DeepEqual(merged.Sort, byID) || *merged.Limit != 2 {
+			t.Fatalf("expected default sort with the caller's limit, got %+v", merged)
+		}
+		if caller.Sort != nil {
+			t.Fatal("expected the caller's options to be left unchanged")
+		}
+
+		explicit := &options.FindOptions{Sort: bson.D{{Key: "age", Value: -1}}}
+		opts = model.withDefaultSort([]*options.FindOptions{caller, explicit})
+		if len(opts) != 2 || opts[1] != explicit {
+			t.Fatal("expected an explicit sort to take precedence")
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("default_sort_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "default_sort_users", WithDefaultSort(byID))
+	for _, u := range []testUser{
+		{ID: "2", Name: "Bob", Age: 35},
+		{ID: "3", Name: "Carol", Age: 25},
+		{ID: "1", Name: "Alice", Age: 30},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(users []testUser) string {
+		var names []string
+		for _, u := range users {
+			names = append(names, u.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	t.Run("default", func(t *testing.T) {
+		users, err := model.FindMany(ctx, bson.D{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(users); got != "Alice,Bob,Carol" {
+			t.Fatalf("expected Alice,Bob,Carol, got %s", got)
+		}
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		users, err := model.FindMany(ctx, bson.D{}, &options.FindOptions{Sort: bson.D{{Key: "age", Value: -1}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := names(users); got != "Bob,Alice,Carol" {
+			t.Fatalf("expected Bob,Alice,Carol, got %s", got)
+		}
+	})
+}
+
+func TestWithAnalyticsRouting(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		servers = make(map[string]string)
+	)
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if e.CommandName == "find" || e.CommandName == "insert" {
+				mu.Lock()
+				servers[e.CommandName] = connectionAddress(e.ConnectionID)
+				mu.Unlock()
+			}
+		},
+	}
+	db := testDatabase(t, withCommandMonitor(monitor))
+	requireReplicaSet(t, db)
+
+	var config struct {
+		Config struct {
+			Members []struct {
+				Host string            `bson:"host"`
+				Tags map[string]string `bson:"tags"`
+			} `bson:"members"`
+		} `bson:"config"`
+	}
+	admin := db.Client().Database("admin")
+	if err := admin.RunCommand(ctx, bson.D{{Key: "replSetGetConfig", Value: 1}}).Decode(&config); err != nil {
+		t.Fatal(err)
+	}
+	var hello struct {
+		Primary string `bson:"primary"`
+	}
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		t.Fatal(err)
+	}
+
+	var tagKey, tagValue string
+	for _, member := range config.Config.Members {
+		if member.Host == hello.Primary {
+			continue
+		}
+		for key, value := range member.Tags {
+			tagKey, tagValue = key, value
+		}
+	}
+	if tagKey == "" {
+		t.Skip("requires a tagged secondary")
+	}
+	tagged := make(map[string]bool)
+	for _, member := range config.Config.Members {
+		if member.Host != hello.Primary && member.Tags[tagKey] == tagValue {
+			tagged[member.Host] = true
+		}
+	}
+
+	_ = db.Collection("analytics_users").Drop(ctx)
+	model := New[testUser, testUser](db, "analytics_users", WithAnalyticsRouting(tagKey, tagValue))
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.FindMany(ctx, bson.D{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if servers["insert"] != hello.Primary {
+		t.Fatalf("expected the write on the primary %s, got %s", hello.Primary, servers["insert"])
+	}
+	if !tagged[servers["find"]] {
+		t.Fatalf("expected the read on a member tagged %s: %s, got %s", tagKey, tagValue, servers["find"])
+	}
+}
+
+func TestWithMaxDocSize(t *testing.T) {
+	model := &mongoModel[testUser, testUser]{config: modelConfig{maxDocSize: 1024}}
+
+	err := model.Create(context.Background(), testUser{ID: "1", Name: strings.Repeat("a", 2048)})
+	if !errors.Is(err, ErrDocumentTooLarge) {
+		t.Fatalf("expected ErrDocumentTooLarge, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "limit of 1024 bytes") {
+		t.Fatalf("expected the size in the error, got %v", err)
+	}
+
+	t.Run("within limit", func(t *testing.T) {
+		if err := model.checkDocSize(testUser{ID: "1", Name: "Alice"}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		ctx := context.Background()
+		db := testDatabase(t)
+		_ = db.Collection("sized_users").Drop(ctx)
+
+		model := New[testUser, testUser](db, "sized_users", WithMaxDocSize(1024))
+		input := `{"_id": "1", "name": "Alice"}` + "\n" + `{"_id": "2", "name": "` + strings.Repeat("b", 2048) + `"}`
+		inserted, err := model.ImportJSONL(ctx, strings.NewReader(input), 10)
+		if inserted != 1 {
+			t.Fatalf("expected 1 inserted, got %d", inserted)
+		}
+		var lineErr *LineError
+		if !errors.As(err, &lineErr) || lineErr.Line != 2 || !errors.Is(err, ErrDocumentTooLarge) {
+			t.Fatalf("expected line 2 to be too large, got %v", err)
+		}
+	})
+}
+
+func TestWithDefault(t *testing.T) {
+	field := func(o *options.UpdateManyOptions) *any { return &o.Comment }
+
+	opts := withDefault(nil, "tagged", field)
+	if len(opts) != 1 || opts[0].Comment != "tagged" {
+		t.Fatalf("expected the tag comment, got %+v", opts)
+	}
+
+	upsert := true
+	caller := &options.UpdateManyOptions{Upsert: &upsert}
+	merged := listOptions(t, BuildUpdateManyOptions(withDefault([]*options.UpdateManyOptions{caller}, "tagged", field)...))
+	if merged.Comment != "tagged" || !*merged.Upsert || caller.Comment != nil {
+		t.Fatalf("expected the tag merged with the caller options, got %+v", merged)
+	}
+
+	caller.Comment = "mine"
+	merged = listOptions(t, BuildUpdateManyOptions(withDefault([]*options.UpdateManyOptions{nil, caller}, "tagged", field)...))
+	if merged.Comment != "mine" {
+		t.Fatalf("expected the caller comment to win, got %v", merged.Comment)
+	}
+}
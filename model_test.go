@@ -33,18 +33,62 @@ type testOrder struct {
 	} `bson:"items"`
 }
 
-func TestMongoModel(t *testing.T) {
-	ctx := context.Background()
+func testDatabase(t testing.TB, opts ...ConnectorOption) *mongo.Database {
+	t.Helper()
 	uri := os.Getenv("MONGODB_URI")
 	dbName := os.Getenv("DATABASE_NAME")
 
 	if uri == "" || dbName == "" {
 		t.Skip("env not set")
 	}
-	db, err := NewConnector(dbName, uri).Connect()
+	db, err := NewConnector(dbName, uri, opts...).Connect()
 	if err != nil {
 		t.Fatalf("connect error: %v", err)
 	}
+	t.Cleanup(func() {
+		_ = db.Client().Disconnect(context.Background())
+	})
+	return db
+}
+
+// requireReplicaSet skips the test unless db is served by a replica set.
+func requireReplicaSet(t testing.TB, db *mongo.Database) {
+	t.Helper()
+	var hello struct {
+		SetName string `bson:"setName"`
+	}
+	err := db.RunCommand(context.Background(), map[string]any{"hello": 1}).Decode(&hello)
+	if err != nil {
+		t.Fatalf("hello error: %v", err)
+	}
+	if hello.SetName == "" {
+		t.Skip("requires a replica set")
+	}
+}
+
+// requireServerVersion skips the test unless the server is at least
+// major.minor.
+func requireServerVersion(t testing.TB, db *mongo.Database, major, minor int) {
+	t.Helper()
+	var info struct {
+		VersionArray []int32 `bson:"versionArray"`
+	}
+	err := db.RunCommand(context.Background(), map[string]any{"buildInfo": 1}).Decode(&info)
+	if err != nil {
+		t.Fatalf("buildInfo error: %v", err)
+	}
+	if len(info.VersionArray) < 2 {
+		t.Fatalf("unexpected version %v", info.VersionArray)
+	}
+	got := [2]int{int(info.VersionArray[0]), int(info.VersionArray[1])}
+	if got[0] < major || got[0] == major && got[1] < minor {
+		t.Skipf("requires MongoDB %d.%d, got %d.%d", major, minor, got[0], got[1])
+	}
+}
+
+func TestMongoModel(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
 	_ = db.Collection("users").Drop(ctx)
 
 	model := New[testUser, testEmployee](db, "users")
@@ -0,0 +1,40 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// CurrentOps returns the operations of every user that have been running
+// for at least minSecs seconds, as reported by $currentOp, e.g. to find
+// runaway queries during an incident. Each entry holds fields such as
+// opid, op, ns, command and microsecs_running.
+//
+// Only active operations are listed; idle connections and cursors are
+// not. Listing other users' operations requires the inprog privilege.
+func (d *Database) CurrentOps(ctx context.Context, minSecs float64) ([]bson.M, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$currentOp", Value: bson.D{{Key: "allUsers", Value: true}}}},
+		{{Key: "$match", Value: bson.D{{Key: "microsecs_running", Value: bson.D{
+			{Key: "$gte", Value: int64(minSecs * 1e6)},
+		}}}}},
+	}
+	cursor, err := d.Client().Database("admin").Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCursor[bson.M](ctx, cursor)
+}
+
+// KillOp terminates the operation with the given opid, as returned by
+// CurrentOps. On sharded clusters opid is the "shard:opid" string.
+//
+// The operation is only marked for termination; it stops at its next
+// interruption point. Killing an operation that already finished is not
+// an error.
+func (d *Database) KillOp(ctx context.Context, opid any) error {
+	cmd := bson.D{{Key: "killOp", Value: 1}, {Key: "op", Value: opid}}
+	return d.Client().Database("admin").RunCommand(ctx, cmd).Err()
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestCurrentOpsAndKillOp(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	_ = db.Collection("ops_users").Drop(ctx)
+
+	model := New[testUser, testUser](db.Database, "ops_users")
+	if err := model.Create(ctx, testUser{ID: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The $where clause keeps the query running until it is killed.
+	comment := "ops-test-" + bson.NewObjectID().Hex()
+	done := make(chan error, 1)
+	go func() {
+		_, err := model.FindMany(
+			ctx,
+			bson.D{{Key: "$where", Value: "sleep(10000) || true"}},
+			&options.FindOptions{Comment: comment},
+		)
+		done <- err
+	}()
+
+	var opid any
+	for deadline := time.Now().Add(5 * time.Second); opid == nil && time.Now().Before(deadline); {
+		ops, err := db.CurrentOps(ctx, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ops) == 0 {
+			t.Fatal("expected at least the $currentOp aggregation itself")
+		}
+		for _, op := range ops {
+			cmd, _ := op["command"].(bson.D)
+			for _, e := range cmd {
+				if e.Key == "comment" && e.Value == comment {
+					opid = op["opid"]
+				}
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if opid == nil {
+		t.Fatal("expected the slow query in the current operations")
+	}
+
+	if err := db.KillOp(ctx, opid); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected the killed query to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the query to be killed")
+	}
+}
@@ -4,6 +4,13 @@ import (
 	"go.mongodb.org/mongo-driver/v2/mongo/options"
 )
 
+// The Build*Options functions convert the option structs accepted by the
+// model methods into the listers the driver expects.
+//
+// When several option values are passed, they are merged in order: each
+// field set in a value overrides the same field of the values before it,
+// and fields left unset keep their earlier value. Nil values are skipped.
+
 func BuildDatabaseOptions(
 	opts *options.DatabaseOptions,
 ) options.Lister[options.DatabaseOptions] {
@@ -30,8 +37,10 @@ func BuildFindManyOptions(
 	opts ...*options.FindOptions,
 ) options.Lister[options.FindOptions] {
 	findOpts := options.Find()
-	if len(opts) > 0 {
-		opts := opts[0]
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
 		findOpts = setOption(findOpts, opts.AllowDiskUse, findOpts.SetAllowDiskUse)
 		findOpts = setOption(findOpts, opts.AllowPartialResults, findOpts.SetAllowPartialResults)
 		findOpts = setOption(findOpts, opts.BatchSize, findOpts.SetBatchSize)
@@ -39,20 +48,19 @@ func BuildFindManyOptions(
 		findOpts = setOption(findOpts, opts.Skip, findOpts.SetSkip)
 		findOpts = setOption(findOpts, opts.MaxAwaitTime, findOpts.SetMaxAwaitTime)
 		findOpts = setOption(findOpts, opts.NoCursorTimeout, findOpts.SetNoCursorTimeout)
-		findOpts = setOption(findOpts, &opts.Sort, findOpts.SetSort)
-		findOpts = setOption(findOpts, &opts.Comment, findOpts.SetComment)
-		findOpts = setOption(findOpts, &opts.Hint, findOpts.SetHint)
-		findOpts = setOption(findOpts, &opts.Let, findOpts.SetLet)
-		findOpts = setOption(findOpts, &opts.Max, findOpts.SetMax)
-		findOpts = setOption(findOpts, &opts.Min, findOpts.SetMin)
-		findOpts = setOption(findOpts, &opts.Projection, findOpts.SetProjection)
+		findOpts = setValue(findOpts, opts.Sort, findOpts.SetSort)
+		findOpts = setValue(findOpts, opts.Comment, findOpts.SetComment)
+		findOpts = setValue(findOpts, opts.Hint, findOpts.SetHint)
+		findOpts = setValue(findOpts, opts.Let, findOpts.SetLet)
+		findOpts = setValue(findOpts, opts.Max, findOpts.SetMax)
+		findOpts = setValue(findOpts, opts.Min, findOpts.SetMin)
+		findOpts = setValue(findOpts, opts.Projection, findOpts.SetProjection)
 		findOpts = setOption(findOpts, opts.ReturnKey, findOpts.SetReturnKey)
+		findOpts = setOption(findOpts, opts.ShowRecordID, findOpts.SetShowRecordID)
+		findOpts = setOption(findOpts, opts.CursorType, findOpts.SetCursorType)
 		if opts.Collation != nil {
 			findOpts = findOpts.SetCollation(opts.Collation)
 		}
-		if opts.CursorType != nil {
-			findOpts = findOpts.SetCursorType(*opts.CursorType)
-		}
 	}
 	return findOpts
 }
@@ -61,17 +69,20 @@ func BuildFindOneOptions(
 	opts ...*options.FindOneOptions,
 ) options.Lister[options.FindOneOptions] {
 	findOneOpts := options.FindOne()
-	if len(opts) > 0 {
-		opts := opts[0]
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
 		findOneOpts = setOption(findOneOpts, opts.AllowPartialResults, findOneOpts.SetAllowPartialResults)
 		findOneOpts = setOption(findOneOpts, opts.Skip, findOneOpts.SetSkip)
-		findOneOpts = setOption(findOneOpts, &opts.Sort, findOneOpts.SetSort)
-		findOneOpts = setOption(findOneOpts, &opts.Comment, findOneOpts.SetComment)
-		findOneOpts = setOption(findOneOpts, &opts.Hint, findOneOpts.SetHint)
-		findOneOpts = setOption(findOneOpts, &opts.Max, findOneOpts.SetMax)
-		findOneOpts = setOption(findOneOpts, &opts.Min, findOneOpts.SetMin)
-		findOneOpts = setOption(findOneOpts, &opts.Projection, findOneOpts.SetProjection)
+		findOneOpts = setValue(findOneOpts, opts.Sort, findOneOpts.SetSort)
+		findOneOpts = setValue(findOneOpts, opts.Comment, findOneOpts.SetComment)
+		findOneOpts = setValue(findOneOpts, opts.Hint, findOneOpts.SetHint)
+		findOneOpts = setValue(findOneOpts, opts.Max, findOneOpts.SetMax)
+		findOneOpts = setValue(findOneOpts, opts.Min, findOneOpts.SetMin)
+		findOneOpts = setValue(findOneOpts, opts.Projection, findOneOpts.SetProjection)
 		findOneOpts = setOption(findOneOpts, opts.ReturnKey, findOneOpts.SetReturnKey)
+		findOneOpts = setOption(findOneOpts, opts.ShowRecordID, findOneOpts.SetShowRecordID)
 		if opts.Collation != nil {
 			findOneOpts = findOneOpts.SetCollation(opts.Collation)
 		}
@@ -83,14 +94,18 @@ func BuildUpdateOneOptions(
 	opts ...*options.UpdateOneOptions,
 ) options.Lister[options.UpdateOneOptions] {
 	updateOneOpts := options.UpdateOne()
-	if len(opts) > 0 {
-		opts := opts[0]
-		updateOneOpts = setOption(updateOneOpts, &opts.ArrayFilters, updateOneOpts.SetArrayFilters)
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
+		if opts.ArrayFilters != nil {
+			updateOneOpts = updateOneOpts.SetArrayFilters(opts.ArrayFilters)
+		}
 		updateOneOpts = setOption(updateOneOpts, opts.BypassDocumentValidation, updateOneOpts.SetBypassDocumentValidation)
-		updateOneOpts = setOption(updateOneOpts, &opts.Sort, updateOneOpts.SetSort)
-		updateOneOpts = setOption(updateOneOpts, &opts.Comment, updateOneOpts.SetComment)
-		updateOneOpts = setOption(updateOneOpts, &opts.Hint, updateOneOpts.SetHint)
-		updateOneOpts = setOption(updateOneOpts, &opts.Let, updateOneOpts.SetLet)
+		updateOneOpts = setValue(updateOneOpts, opts.Sort, updateOneOpts.SetSort)
+		updateOneOpts = setValue(updateOneOpts, opts.Comment, updateOneOpts.SetComment)
+		updateOneOpts = setValue(updateOneOpts, opts.Hint, updateOneOpts.SetHint)
+		updateOneOpts = setValue(updateOneOpts, opts.Let, updateOneOpts.SetLet)
 		updateOneOpts = setOption(updateOneOpts, opts.Upsert, updateOneOpts.SetUpsert)
 		if opts.Collation != nil {
 			updateOneOpts = updateOneOpts.SetCollation(opts.Collation)
@@ -104,22 +119,62 @@ func BuildUpdateManyOptions(
 	opts ...*options.UpdateManyOptions,
 ) options.Lister[options.UpdateManyOptions] {
 	updateManyOpts := options.UpdateMany()
-	if len(opts) > 0 {
-		opts := opts[0]
-		updateManyOpts = setOption(updateManyOpts, &opts.ArrayFilters, updateManyOpts.SetArrayFilters)
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
+		if opts.ArrayFilters != nil {
+			updateManyOpts = updateManyOpts.SetArrayFilters(opts.ArrayFilters)
+		}
 		updateManyOpts = setOption(updateManyOpts, opts.BypassDocumentValidation, updateManyOpts.SetBypassDocumentValidation)
-		updateManyOpts = setOption(updateManyOpts, &opts.Comment, updateManyOpts.SetComment)
-		updateManyOpts = setOption(updateManyOpts, &opts.Hint, updateManyOpts.SetHint)
-		updateManyOpts = setOption(updateManyOpts, &opts.Let, updateManyOpts.SetLet)
+		updateManyOpts = setValue(updateManyOpts, opts.Comment, updateManyOpts.SetComment)
+		updateManyOpts = setValue(updateManyOpts, opts.Hint, updateManyOpts.SetHint)
+		updateManyOpts = setValue(updateManyOpts, opts.Let, updateManyOpts.SetLet)
 		updateManyOpts = setOption(updateManyOpts, opts.Upsert, updateManyOpts.SetUpsert)
 		if opts.Collation != nil {
 			updateManyOpts = updateManyOpts.SetCollation(opts.Collation)
 		}
-
 	}
 	return updateManyOpts
 }
 
+func BuildDeleteOneOptions(
+	opts ...*options.DeleteOneOptions,
+) options.Lister[options.DeleteOneOptions] {
+	deleteOneOpts := options.DeleteOne()
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
+		deleteOneOpts = setValue(deleteOneOpts, opts.Comment, deleteOneOpts.SetComment)
+		deleteOneOpts = setValue(deleteOneOpts, opts.Hint, deleteOneOpts.SetHint)
+		deleteOneOpts = setValue(deleteOneOpts, opts.Let, deleteOneOpts.SetLet)
+		if opts.Collation != nil {
+			deleteOneOpts = deleteOneOpts.SetCollation(opts.Collation)
+		}
+	}
+	return deleteOneOpts
+}
+
+func BuildDeleteManyOptions(
+	opts ...*options.DeleteManyOptions,
+) options.Lister[options.DeleteManyOptions] {
+	deleteManyOpts := options.DeleteMany()
+	for _, opts := range opts {
+		if opts == nil {
+			continue
+		}
+		deleteManyOpts = setValue(deleteManyOpts, opts.Comment, deleteManyOpts.SetComment)
+		deleteManyOpts = setValue(deleteManyOpts, opts.Hint, deleteManyOpts.SetHint)
+		deleteManyOpts = setValue(deleteManyOpts, opts.Let, deleteManyOpts.SetLet)
+		if opts.Collation != nil {
+			deleteManyOpts = deleteManyOpts.SetCollation(opts.Collation)
+		}
+	}
+	return deleteManyOpts
+}
+
+// setOption sets a pointer option on builder when value is set.
 func setOption[O any, V any](
 	builder *O,
 	value *V,
@@ -130,3 +185,17 @@ func setOption[O any, V any](
 	}
 	return builder
 }
+
+// setValue sets an untyped option, such as a sort or projection, on
+// builder when value is set. Unlike taking the field's address, which is
+// never nil, this keeps an unset field from clearing an earlier value.
+func setValue[O any](
+	builder *O,
+	value any,
+	set func(any) *O,
+) *O {
+	if value != nil {
+		return set(value)
+	}
+	return builder
+}
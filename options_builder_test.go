@@ -0,0 +1,185 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// listOptions applies every setter of lister, as the driver does.
+func listOptions[O any](t testing.TB, lister options.Lister[O]) *O {
+	t.Helper()
+	var opts O
+	for _, set := range lister.List() {
+		if err := set(&opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return &opts
+}
+
+func TestBuildOptions(t *testing.T) {
+	limit := int64(10)
+	sort := bson.D{{Key: "age", Value: -1}}
+	projection := bson.D{{Key: "name", Value: 1}}
+
+	t.Run("FindMany", func(t *testing.T) {
+		opts := listOptions(t, BuildFindManyOptions(&options.FindOptions{
+			Limit:      &limit,
+			Sort:       sort,
+			Projection: projection,
+		}))
+		if opts.Limit == nil || *opts.Limit != 10 {
+			t.Fatalf("expected limit 10, got %v", opts.Limit)
+		}
+		if !reflect.DeepEqual(opts.Sort, sort) {
+			t.Fatalf("expected sort %v, got %v", sort, opts.Sort)
+		}
+		if !reflect.DeepEqual(opts.Projection, projection) {
+			t.Fatalf("expected projection %v, got %v", projection, opts.Projection)
+		}
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		skip := int64(1)
+		opts := listOptions(t, BuildFindOneOptions(&options.FindOneOptions{
+			Skip:       &skip,
+			Sort:       sort,
+			Projection: projection,
+		}))
+		if opts.Skip == nil || *opts.Skip != 1 {
+			t.Fatalf("expected skip 1, got %v", opts.Skip)
+		}
+		if !reflect.DeepEqual(opts.Sort, sort) {
+			t.Fatalf("expected sort %v, got %v", sort, opts.Sort)
+		}
+		if !reflect.DeepEqual(opts.Projection, projection) {
+			t.Fatalf("expected projection %v, got %v", projection, opts.Projection)
+		}
+	})
+
+	t.Run("UpdateOne", func(t *testing.T) {
+		upsert := true
+		filters := []any{bson.D{{Key: "item.sku", Value: "A1"}}}
+		opts := listOptions(t, BuildUpdateOneOptions(&options.UpdateOneOptions{
+			ArrayFilters: filters,
+			Sort:         sort,
+			Upsert:       &upsert,
+		}))
+		if !reflect.DeepEqual(opts.ArrayFilters, filters) || !reflect.DeepEqual(opts.Sort, sort) {
+			t.Fatalf("expected array filters and sort, got %+v", opts)
+		}
+		if opts.Upsert == nil || !*opts.Upsert {
+			t.Fatalf("expected upsert, got %v", opts.Upsert)
+		}
+	})
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		upsert := true
+		opts := listOptions(t, BuildUpdateManyOptions(&options.UpdateManyOptions{
+			Hint:   "age_1",
+			Upsert: &upsert,
+		}))
+		if opts.Hint != "age_1" || opts.Upsert == nil || !*opts.Upsert {
+			t.Fatalf("expected hint and upsert, got %+v", opts)
+		}
+	})
+
+	t.Run("merged in order", func(t *testing.T) {
+		other := int64(5)
+		opts := listOptions(t, BuildFindManyOptions(
+			&options.FindOptions{Limit: &limit, Sort: sort},
+			nil,
+			&options.FindOptions{Limit: &other, Projection: projection},
+		))
+		if *opts.Limit != 5 {
+			t.Fatalf("expected the later limit 5, got %d", *opts.Limit)
+		}
+		if !reflect.DeepEqual(opts.Sort, sort) {
+			t.Fatalf("expected the earlier sort to be kept, got %v", opts.Sort)
+		}
+		if !reflect.DeepEqual(opts.Projection, projection) {
+			t.Fatalf("expected projection %v, got %v", projection, opts.Projection)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		opts := listOptions(t, BuildFindManyOptions())
+		if !reflect.DeepEqual(*opts, options.FindOptions{}) {
+			t.Fatalf("expected empty options, got %+v", opts)
+		}
+	})
+}
+
+func TestOptionsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("options_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "options_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30},
+		{ID: "2", Name: "Bob", Email: "bob@test.com", Age: 35},
+		{ID: "3", Name: "Carol", Email: "carol@test.com", Age: 25},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	limit := int64(2)
+	byAge := bson.D{{Key: "age", Value: -1}}
+	nameOnly := bson.D{{Key: "name", Value: 1}}
+
+	t.Run("FindMany", func(t *testing.T) {
+		users, err := model.FindMany(ctx, bson.D{}, &options.FindOptions{Limit: &limit, Sort: byAge, Projection: nameOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 2 || users[0].Name != "Bob" || users[1].Name != "Alice" {
+			t.Fatalf("expected [Bob Alice], got %+v", users)
+		}
+		if users[0].Email != "" {
+			t.Fatalf("expected the projection to drop email, got %+v", users[0])
+		}
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		user, err := model.FindOne(ctx, bson.D{}, &options.FindOneOptions{Sort: byAge, Projection: nameOnly})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Bob" || user.Email != "" {
+			t.Fatalf("expected Bob without email, got %+v", user)
+		}
+	})
+
+	t.Run("UpdateOne", func(t *testing.T) {
+		requireServerVersion(t, db, 8, 0)
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "position", Value: "Lead"}}}}
+		if err := model.UpdateOne(ctx, bson.D{}, update, &options.UpdateOneOptions{Sort: byAge}); err != nil {
+			t.Fatal(err)
+		}
+		user, err := model.FindOne(ctx, bson.D{{Key: "position", Value: "Lead"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Bob" {
+			t.Fatalf("expected the sort to update Bob, got %+v", user)
+		}
+	})
+
+	t.Run("UpdateMany", func(t *testing.T) {
+		upsert := true
+		update := bson.D{{Key: "$set", Value: bson.D{{Key: "age", Value: 40}}}}
+		if err := model.UpdateMany(ctx, bson.D{{Key: "name", Value: "Dave"}}, update, &options.UpdateManyOptions{Upsert: &upsert}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := model.FindOne(ctx, bson.D{{Key: "name", Value: "Dave"}}); err != nil {
+			t.Fatalf("expected the upsert to insert Dave, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultParallelism bounds the concurrent finds of ParallelFind unless
+// WithParallelism is given.
+const defaultParallelism = 4
+
+// ParallelQuery is a find run by ParallelFind.
+type ParallelQuery struct {
+	// Collection is the name of the collection to query.
+	Collection string
+
+	// Filter selects the documents. Nil matches every document.
+	Filter any
+
+	// Options optionally configures the find, e.g. its sort or limit.
+	Options *options.FindOptions
+}
+
+// ParallelResult is the outcome of a ParallelQuery.
+type ParallelResult struct {
+	// Documents are the matched documents, to be decoded with
+	// bson.Unmarshal into the type of their collection.
+	Documents []bson.Raw
+
+	// Err is the error of the query, if it failed or was cancelled.
+	Err error
+}
+
+// ParallelFindOption configures a ParallelFind call.
+type ParallelFindOption func(*parallelFindConfig)
+
+// parallelFindConfig holds the settings collected from ParallelFindOption values.
+type parallelFindConfig struct {
+	// parallelism is the maximum number of concurrent finds.
+	parallelism int
+
+	// continueOnError keeps running the other queries when one fails.
+	continueOnError bool
+}
+
+// WithParallelism sets how many finds ParallelFind runs at once. Values
+// below one are ignored.
+func WithParallelism(n int) ParallelFindOption {
+	return func(c *parallelFindConfig) {
+		if n > 0 {
+			c.parallelism = n
+		}
+	}
+}
+
+// WithContinueOnError makes ParallelFind run every query even when some
+// fail, instead of cancelling the others on the first failure.
+func WithContinueOnError() ParallelFindOption {
+	return func(c *parallelFindConfig) {
+		c.continueOnError = true
+	}
+}
+
+// ParallelFind runs queries concurrently, e.g. to load the collections of
+// a dashboard at once, and returns one result per query, in order.
+//
+// At most four finds run at a time unless WithParallelism says
+// otherwise. By default the first failure cancels the queries still
+// running and is returned as the error; the cancelled queries report the
+// cancellation in their Err. With WithContinueOnError every query runs to
+// completion and the failures are returned joined.
+func (d *Database) ParallelFind(
+	ctx context.Context,
+	queries []ParallelQuery,
+	opts ...ParallelFindOption,
+) ([]ParallelResult, error) {
+	config := parallelFindConfig{parallelism: defaultParallelism}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	if config.continueOnError {
+		group, groupCtx = &errgroup.Group{}, ctx
+	}
+	group.SetLimit(config.parallelism)
+
+	results := make([]ParallelResult, len(queries))
+	for i, query := range queries {
+		group.Go(func() error {
+			docs, err := d.find(groupCtx, query)
+			results[i] = ParallelResult{Documents: docs, Err: err}
+			if config.continueOnError {
+				return nil
+			}
+			return err
+		})
+	}
+	err := group.Wait()
+
+	if config.continueOnError {
+		var errs []error
+		for _, result := range results {
+			errs = append(errs, result.Err)
+		}
+		err = errors.Join(errs...)
+	}
+	return results, err
+}
+
+// find runs a single ParallelQuery.
+func (d *Database) find(ctx context.Context, query ParallelQuery) ([]bson.Raw, error) {
+	filter := query.Filter
+	if filter == nil {
+		filter = bson.D{}
+	}
+	var findOpts []*options.FindOptions
+	if query.Options != nil {
+		findOpts = append(findOpts, query.Options)
+	}
+
+	cursor, err := d.Collection(query.Collection).Find(ctx, filter, BuildFindManyOptions(findOpts...))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	docs := make([]bson.Raw, 0)
+	for cursor.Next(ctx) {
+		// Current is reused by the cursor, so keep a copy.
+		docs = append(docs, append(bson.Raw(nil), cursor.Current...))
+	}
+	return docs, cursor.Err()
+}
@@ -0,0 +1,93 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestParallelFind(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+
+	seed := map[string][]testUser{
+		"parallel_users":  {{ID: "1", Name: "Alice"}, {ID: "2", Name: "Bob"}},
+		"parallel_admins": {{ID: "3", Name: "Carol"}},
+		"parallel_guests": {{ID: "4", Name: "Dave"}, {ID: "5", Name: "Erin"}, {ID: "6", Name: "Frank"}},
+	}
+	for name, users := range seed {
+		_ = db.Collection(name).Drop(ctx)
+		model := New[testUser, testUser](db.Database, name)
+		for _, u := range users {
+			if err := model.Create(ctx, u); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("results", func(t *testing.T) {
+		limit := int64(2)
+		results, err := db.ParallelFind(ctx, []ParallelQuery{
+			{Collection: "parallel_users"},
+			{Collection: "parallel_admins", Filter: bson.D{{Key: "name", Value: "Carol"}}},
+			{Collection: "parallel_guests", Options: &options.FindOptions{Limit: &limit}},
+		}, WithParallelism(2))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i, expected := range []int{2, 1, 2} {
+			if results[i].Err != nil || len(results[i].Documents) != expected {
+				t.Fatalf("expected %d documents for query %d, got %+v", expected, i, results[i])
+			}
+		}
+		var admin testUser
+		if err := bson.Unmarshal(results[1].Documents[0], &admin); err != nil {
+			t.Fatal(err)
+		}
+		if admin.Name != "Carol" {
+			t.Fatalf("expected Carol, got %+v", admin)
+		}
+	})
+
+	// The $where clause keeps the query running long enough to be cancelled.
+	slow := ParallelQuery{
+		Collection: "parallel_guests",
+		Filter:     bson.D{{Key: "$where", Value: "sleep(1000) || true"}},
+	}
+	failing := ParallelQuery{
+		Collection: "parallel_users",
+		Filter:     bson.D{{Key: "$unknownOperator", Value: 1}},
+	}
+
+	t.Run("cancel on first error", func(t *testing.T) {
+		start := time.Now()
+		results, err := db.ParallelFind(ctx, []ParallelQuery{slow, failing})
+		if err == nil || !errors.Is(err, results[1].Err) {
+			t.Fatalf("expected the failing query error, got %v", err)
+		}
+		if results[0].Err == nil {
+			t.Fatal("expected the slow query to be cancelled")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("expected cancellation, took %v", elapsed)
+		}
+	})
+
+	t.Run("continue on error", func(t *testing.T) {
+		results, err := db.ParallelFind(ctx, []ParallelQuery{
+			{Collection: "parallel_users"},
+			failing,
+		}, WithContinueOnError())
+		if err == nil || !errors.Is(err, results[1].Err) {
+			t.Fatalf("expected the failing query error, got %v", err)
+		}
+		if results[0].Err != nil || len(results[0].Documents) != 2 {
+			t.Fatalf("expected the other query to complete, got %+v", results[0])
+		}
+	})
+}
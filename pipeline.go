@@ -0,0 +1,278 @@
+package mongodb
+
+import (
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// PipelineBuilder builds an aggregation pipeline stage by stage.
+//
+// Each stage method appends a stage and returns the same builder, so
+// stages can be chained and passed to Aggregate through Build:
+//
+//	pipeline := Pipeline().
+//		Match(bson.D{{Key: "active", Value: true}}).
+//		GraphLookup("employees", "$reports_to", "reports_to", "_id", "managers", -1).
+//		Build()
+type PipelineBuilder struct {
+	stages mongo.Pipeline
+}
+
+// Pipeline starts a new, empty aggregation pipeline.
+func Pipeline() *PipelineBuilder {
+	return &PipelineBuilder{stages: mongo.Pipeline{}}
+}
+
+// Stage appends a raw stage, for stages without a dedicated builder.
+func (p *PipelineBuilder) Stage(stage bson.D) *PipelineBuilder {
+	p.stages = append(p.stages, stage)
+	return p
+}
+
+// Match appends a $match stage filtering the documents.
+func (p *PipelineBuilder) Match(filter any) *PipelineBuilder {
+	return p.Stage(bson.D{{Key: "$match", Value: filter}})
+}
+
+// GraphLookup appends a $graphLookup stage performing a recursive search
+// on the from collection, e.g. to collect every ancestor in a hierarchy.
+//
+// The search starts with the value of the startWith expression and follows
+// connectFromField to connectToField, storing the visited documents in the
+// as field. A negative maxDepth leaves the recursion depth unbounded.
+func (p *PipelineBuilder) GraphLookup(
+	from string,
+	startWith any,
+	connectFromField string,
+	connectToField string,
+	as string,
+	maxDepth int64,
+) *PipelineBuilder {
+	stage := bson.D{
+		{Key: "from", Value: from},
+		{Key: "startWith", Value: startWith},
+		{Key: "connectFromField", Value: connectFromField},
+		{Key: "connectToField", Value: connectToField},
+		{Key: "as", Value: as},
+	}
+	if maxDepth >= 0 {
+		stage = append(stage, bson.E{Key: "maxDepth", Value: maxDepth})
+	}
+	return p.Stage(bson.D{{Key: "$graphLookup", Value: stage}})
+}
+
+// SetWindowFields appends a $setWindowFields stage computing window
+// functions, such as running totals, over partitions of the documents.
+//
+// partitionBy and sortBy are optional and omitted when nil. output maps
+// each computed field to its window operator, e.g.
+//
+//	bson.D{{Key: "total", Value: bson.D{
+//		{Key: "$sum", Value: "$amount"},
+//		{Key: "window", Value: bson.D{{Key: "documents", Value: bson.A{"unbounded", "current"}}}},
+//	}}}
+//
+// The stage requires MongoDB 5.0 or later.
+func (p *PipelineBuilder) SetWindowFields(partitionBy, sortBy, output any) *PipelineBuilder {
+	stage := bson.D{}
+	if partitionBy != nil {
+		stage = append(stage, bson.E{Key: "partitionBy", Value: partitionBy})
+	}
+	if sortBy != nil {
+		stage = append(stage, bson.E{Key: "sortBy", Value: sortBy})
+	}
+	stage = append(stage, bson.E{Key: "output", Value: output})
+	return p.Stage(bson.D{{Key: "$setWindowFields", Value: stage}})
+}
+
+// Merge appends a $merge stage writing the pipeline results into the
+// into collection of the same database, e.g. to refresh a materialized
+// view. It must be the last stage.
+//
+// whenMatched is one of "replace", "keepExisting", "merge" or "fail",
+// and whenNotMatched one of "insert", "discard" or "fail". Empty values
+// use the server defaults ("merge" and "insert").
+func (p *PipelineBuilder) Merge(into string, whenMatched, whenNotMatched string) *PipelineBuilder {
+	stage := bson.D{{Key: "into", Value: into}}
+	if whenMatched != "" {
+		stage = append(stage, bson.E{Key: "whenMatched", Value: whenMatched})
+	}
+	if whenNotMatched != "" {
+		stage = append(stage, bson.E{Key: "whenNotMatched", Value: whenNotMatched})
+	}
+	return p.Stage(bson.D{{Key: "$merge", Value: stage}})
+}
+
+// Bucket appends a $bucket stage grouping documents into the ranges
+// between consecutive boundaries, e.g. a histogram of ages:
+//
+//	Pipeline().Bucket("$age", []any{0, 18, 65}, "other", nil)
+//
+// boundaries must be sorted ascending; each bucket includes its lower
+// bound and excludes the upper one. Documents outside the boundaries go
+// to the defaultBucket _id, and fail the aggregation when it is nil.
+// output defines the fields of each bucket and defaults to a count when
+// nil.
+func (p *PipelineBuilder) Bucket(groupBy any, boundaries []any, defaultBucket, output any) *PipelineBuilder {
+	stage := bson.D{
+		{Key: "groupBy", Value: groupBy},
+		{Key: "boundaries", Value: boundaries},
+	}
+	if defaultBucket != nil {
+		stage = append(stage, bson.E{Key: "default", Value: defaultBucket})
+	}
+	if output != nil {
+		stage = append(stage, bson.E{Key: "output", Value: output})
+	}
+	return p.Stage(bson.D{{Key: "$bucket", Value: stage}})
+}
+
+// BucketAuto appends a $bucketAuto stage grouping documents into the
+// given number of buckets, with boundaries picked by the server to
+// spread the documents evenly. Each bucket _id holds its min and max.
+//
+// output defines the fields of each bucket and defaults to a count when
+// nil.
+func (p *PipelineBuilder) BucketAuto(groupBy any, buckets int, output any) *PipelineBuilder {
+	stage := bson.D{
+		{Key: "groupBy", Value: groupBy},
+		{Key: "buckets", Value: buckets},
+	}
+	if output != nil {
+		stage = append(stage, bson.E{Key: "output", Value: output})
+	}
+	return p.Stage(bson.D{{Key: "$bucketAuto", Value: stage}})
+}
+
+// ReplaceRoot appends a $replaceRoot stage replacing each document with
+// newRoot, e.g. "$address" to promote an embedded document to the root.
+// newRoot must resolve to a document.
+func (p *PipelineBuilder) ReplaceRoot(newRoot any) *PipelineBuilder {
+	return p.Stage(bson.D{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: newRoot}}}})
+}
+
+// ReplaceWith appends a $replaceWith stage, the shorter form of
+// ReplaceRoot taking the replacement expression directly.
+func (p *PipelineBuilder) ReplaceWith(expr any) *PipelineBuilder {
+	return p.Stage(bson.D{{Key: "$replaceWith", Value: expr}})
+}
+
+// Densify appends a $densify stage creating the documents missing from
+// a sequence of field values, e.g. the hours without readings in a time
+// series:
+//
+//	Pipeline().Densify("ts", "full", 1, "hour")
+//
+// bounds is "full", "partition" or a two-element array of the lower and
+// upper bound, and step the distance between values. unit is required
+// for dates, e.g. "hour" or "day", and must be empty for numbers. The
+// created documents only hold field; fill the others with Fill.
+//
+// The stage requires MongoDB 5.1 or later.
+func (p *PipelineBuilder) Densify(field string, bounds, step any, unit string) *PipelineBuilder {
+	rng := bson.D{
+		{Key: "step", Value: step},
+		{Key: "bounds", Value: bounds},
+	}
+	if unit != "" {
+		rng = append(rng, bson.E{Key: "unit", Value: unit})
+	}
+	return p.Stage(bson.D{{Key: "$densify", Value: bson.D{
+		{Key: "field", Value: field},
+		{Key: "range", Value: rng},
+	}}})
+}
+
+// Fill appends a $fill stage setting the null and missing values of the
+// output fields, each with a constant or by interpolation:
+//
+//	Pipeline().Fill(nil, bson.D{{Key: "ts", Value: 1}}, bson.D{
+//		{Key: "value", Value: bson.D{{Key: "method", Value: "linear"}}},
+//		{Key: "unit", Value: bson.D{{Key: "value", Value: "celsius"}}},
+//	})
+//
+// The "linear" and "locf" methods need sortBy, and fill within each
+// partitionBy group when it is set. Nil partitionBy or sortBy are
+// omitted.
+//
+// The stage requires MongoDB 5.3 or later.
+func (p *PipelineBuilder) Fill(partitionBy, sortBy, output any) *PipelineBuilder {
+	stage := bson.D{}
+	if partitionBy != nil {
+		stage = append(stage, bson.E{Key: "partitionBy", Value: partitionBy})
+	}
+	if sortBy != nil {
+		stage = append(stage, bson.E{Key: "sortBy", Value: sortBy})
+	}
+	stage = append(stage, bson.E{Key: "output", Value: output})
+	return p.Stage(bson.D{{Key: "$fill", Value: stage}})
+}
+
+// Function appends an $addFields stage setting field to the result of
+// the JavaScript body run on the server with args, for logic the
+// aggregation operators cannot express:
+//
+//	Pipeline().Function(
+//		"initials",
+//		"function(first, last) { return first[0] + last[0] }",
+//		[]any{"$first_name", "$last_name"},
+//		"",
+//	)
+//
+// lang defaults to "js", the only language the server supports. The body
+// runs with the permissions of the database user, so never build it from
+// user input: that allows arbitrary code execution on the server. It is
+// also much slower than the built-in operators, and fails when the server
+// disables JavaScript with security.javascriptEnabled. $function requires
+// MongoDB 4.4 or later.
+func (p *PipelineBuilder) Function(field, body string, args []any, lang string) *PipelineBuilder {
+	return p.Stage(bson.D{{Key: "$addFields", Value: bson.D{
+		{Key: field, Value: Function(body, args, lang)},
+	}}})
+}
+
+// Function returns the $function expression the Function stage method
+// sets, for use inside other stages such as $project or $group.
+func Function(body string, args []any, lang string) bson.D {
+	if lang == "" {
+		lang = "js"
+	}
+	if args == nil {
+		args = []any{}
+	}
+	return bson.D{{Key: "$function", Value: bson.D{
+		{Key: "body", Value: body},
+		{Key: "args", Value: args},
+		{Key: "lang", Value: lang},
+	}}}
+}
+
+// VectorSearch appends a $vectorSearch stage returning the documents whose
+// path embedding is nearest to queryVector, using the Atlas Vector Search
+// index named index. It must be the first stage.
+//
+// numCandidates is how many nearest neighbors are considered and limit
+// how many are returned; numCandidates should be well above limit for
+// accurate results. Use a "$meta": "vectorSearchScore" projection in a
+// later stage to read the similarity score.
+//
+// The stage is only available on MongoDB Atlas.
+func (p *PipelineBuilder) VectorSearch(
+	index string,
+	path string,
+	queryVector []float32,
+	numCandidates, limit int,
+) *PipelineBuilder {
+	return p.Stage(bson.D{{Key: "$vectorSearch", Value: bson.D{
+		{Key: "index", Value: index},
+		{Key: "path", Value: path},
+		{Key: "queryVector", Value: queryVector},
+		{Key: "numCandidates", Value: numCandidates},
+		{Key: "limit", Value: limit},
+	}}})
+}
+
+// Build returns the assembled pipeline.
+func (p *PipelineBuilder) Build() mongo.Pipeline {
+	return p.stages
+}
@@ -0,0 +1,576 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+type testOrgMember struct {
+	ID        string `bson:"_id"`
+	Name      string `bson:"name"`
+	ReportsTo string `bson:"reports_to,omitempty"`
+}
+
+type testOrgChain struct {
+	ID        string          `bson:"_id"`
+	Name      string          `bson:"name"`
+	Ancestors []testOrgMember `bson:"ancestors"`
+}
+
+func assertPipeline(t *testing.T, got, expected mongo.Pipeline) {
+	t.Helper()
+	gotJSON, err := bson.MarshalExtJSON(bson.D{{Key: "pipeline", Value: got}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJSON, err := bson.MarshalExtJSON(bson.D{{Key: "pipeline", Value: expected}}, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(gotJSON) != string(expectedJSON) {
+		t.Fatalf("expected %s, got %s", expectedJSON, gotJSON)
+	}
+}
+
+func TestPipelineGraphLookup(t *testing.T) {
+	t.Run("BSON", func(t *testing.T) {
+		pipeline := Pipeline().
+			Match(bson.D{{Key: "_id", Value: "dev"}}).
+			GraphLookup("org", "$reports_to", "reports_to", "_id", "ancestors", 2).
+			Build()
+
+		assertPipeline(t, pipeline, mongo.Pipeline{
+			{{Key: "$match", Value: bson.D{{Key: "_id", Value: "dev"}}}},
+			{{Key: "$graphLookup", Value: bson.D{
+				{Key: "from", Value: "org"},
+				{Key: "startWith", Value: "$reports_to"},
+				{Key: "connectFromField", Value: "reports_to"},
+				{Key: "connectToField", Value: "_id"},
+				{Key: "as", Value: "ancestors"},
+				{Key: "maxDepth", Value: int64(2)},
+			}}},
+		})
+	})
+
+	t.Run("unbounded depth", func(t *testing.T) {
+		pipeline := Pipeline().
+			GraphLookup("org", "$reports_to", "reports_to", "_id", "ancestors", -1).
+			Build()
+
+		stage := pipeline[0][0].Value.(bson.D)
+		for _, e := range stage {
+			if e.Key == "maxDepth" {
+				t.Fatal("expected maxDepth to be omitted")
+			}
+		}
+	})
+
+	t.Run("hierarchy", func(t *testing.T) {
+		ctx := context.Background()
+		db := testDatabase(t)
+		_ = db.Collection("org").Drop(ctx)
+
+		members := New[testOrgMember, testOrgChain](db, "org")
+		for _, m := range []testOrgMember{
+			{ID: "ceo", Name: "CEO"},
+			{ID: "cto", Name: "CTO", ReportsTo: "ceo"},
+			{ID: "lead", Name: "Lead", ReportsTo: "cto"},
+			{ID: "dev", Name: "Dev", ReportsTo: "lead"},
+		} {
+			if err := members.Create(ctx, m); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		results, err := members.Aggregate(ctx, Pipeline().
+			Match(bson.D{{Key: "_id", Value: "dev"}}).
+			GraphLookup("org", "$reports_to", "reports_to", "_id", "ancestors", -1).
+			Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("expected 1 result, got %d", len(results))
+		}
+
+		var ancestors []string
+		for _, a := range results[0].Ancestors {
+			ancestors = append(ancestors, a.ID)
+		}
+		sort.Strings(ancestors)
+		if !reflect.DeepEqual(ancestors, []string{"ceo", "cto", "lead"}) {
+			t.Fatalf("unexpected ancestors %v", ancestors)
+		}
+	})
+}
+
+type testSale struct {
+	ID           string `bson:"_id"`
+	Store        string `bson:"store"`
+	Day          int    `bson:"day"`
+	Amount       int    `bson:"amount"`
+	RunningTotal int    `bson:"running_total,omitempty"`
+}
+
+func TestPipelineSetWindowFields(t *testing.T) {
+	runningTotal := bson.D{{Key: "running_total", Value: bson.D{
+		{Key: "$sum", Value: "$amount"},
+		{Key: "window", Value: bson.D{{Key: "documents", Value: bson.A{"unbounded", "current"}}}},
+	}}}
+
+	t.Run("BSON", func(t *testing.T) {
+		pipeline := Pipeline().
+			SetWindowFields("$store", bson.D{{Key: "day", Value: 1}}, runningTotal).
+			Build()
+
+		assertPipeline(t, pipeline, mongo.Pipeline{
+			{{Key: "$setWindowFields", Value: bson.D{
+				{Key: "partitionBy", Value: "$store"},
+				{Key: "sortBy", Value: bson.D{{Key: "day", Value: 1}}},
+				{Key: "output", Value: runningTotal},
+			}}},
+		})
+
+		unpartitioned := Pipeline().SetWindowFields(nil, nil, runningTotal).Build()
+		assertPipeline(t, unpartitioned, mongo.Pipeline{
+			{{Key: "$setWindowFields", Value: bson.D{
+				{Key: "output", Value: runningTotal},
+			}}},
+		})
+	})
+
+	t.Run("running total", func(t *testing.T) {
+		ctx := context.Background()
+		db := testDatabase(t)
+		_ = db.Collection("window_sales").Drop(ctx)
+
+		sales := New[testSale, testSale](db, "window_sales")
+		for _, s := range []testSale{
+			{ID: "a1", Store: "a", Day: 1, Amount: 10},
+			{ID: "a3", Store: "a", Day: 3, Amount: 30},
+			{ID: "a2", Store: "a", Day: 2, Amount: 20},
+			{ID: "b1", Store: "b", Day: 1, Amount: 5},
+			{ID: "b2", Store: "b", Day: 2, Amount: 7},
+		} {
+			if err := sales.Create(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		results, err := sales.Aggregate(ctx, Pipeline().
+			SetWindowFields("$store", bson.D{{Key: "day", Value: 1}}, runningTotal).
+			Stage(bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}}).
+			Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := map[string]int{"a1": 10, "a2": 30, "a3": 60, "b1": 5, "b2": 12}
+		if len(results) != len(expected) {
+			t.Fatalf("expected %d results, got %d", len(expected), len(results))
+		}
+		for _, r := range results {
+			if r.RunningTotal != expected[r.ID] {
+				t.Fatalf("expected running total %d for %s, got %d", expected[r.ID], r.ID, r.RunningTotal)
+			}
+		}
+	})
+}
+
+type testStoreTotal struct {
+	ID    string `bson:"_id"`
+	Total int    `bson:"total"`
+}
+
+func TestPipelineMerge(t *testing.T) {
+	t.Run("BSON", func(t *testing.T) {
+		assertPipeline(t, Pipeline().Merge("totals", "replace", "insert").Build(), mongo.Pipeline{
+			{{Key: "$merge", Value: bson.D{
+				{Key: "into", Value: "totals"},
+				{Key: "whenMatched", Value: "replace"},
+				{Key: "whenNotMatched", Value: "insert"},
+			}}},
+		})
+
+		assertPipeline(t, Pipeline().Merge("totals", "", "").Build(), mongo.Pipeline{
+			{{Key: "$merge", Value: bson.D{{Key: "into", Value: "totals"}}}},
+		})
+	})
+
+	t.Run("AggregateTo requires an output stage", func(t *testing.T) {
+		model := &mongoModel[testSale, testSale]{}
+		err := model.AggregateTo(context.Background(), Pipeline().Match(bson.D{}).Build())
+		if !errors.Is(err, ErrInvalidPipeline) {
+			t.Fatalf("expected ErrInvalidPipeline, got %v", err)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		ctx := context.Background()
+		db := testDatabase(t)
+		_ = db.Collection("merge_sales").Drop(ctx)
+		_ = db.Collection("merge_totals").Drop(ctx)
+
+		totals := New[testStoreTotal, testStoreTotal](db, "merge_totals")
+		for _, total := range []testStoreTotal{
+			{ID: "a", Total: 999},
+			{ID: "z", Total: 1},
+		} {
+			if err := totals.Create(ctx, total); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		sales := New[testSale, testSale](db, "merge_sales")
+		for _, s := range []testSale{
+			{ID: "a1", Store: "a", Amount: 10},
+			{ID: "a2", Store: "a", Amount: 20},
+			{ID: "b1", Store: "b", Amount: 5},
+		} {
+			if err := sales.Create(ctx, s); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		err := sales.AggregateTo(ctx, Pipeline().
+			Stage(bson.D{{Key: "$group", Value: bson.D{
+				{Key: "_id", Value: "$store"},
+				{Key: "total", Value: bson.D{{Key: "$sum", Value: "$amount"}}},
+			}}}).
+			Merge("merge_totals", "replace", "insert").
+			Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := totals.FindMany(ctx, bson.D{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]int)
+		for _, r := range results {
+			got[r.ID] = r.Total
+		}
+		expected := map[string]int{"a": 30, "b": 5, "z": 1}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+}
+
+type testEmbedding struct {
+	ID        string    `bson:"_id"`
+	Embedding []float32 `bson:"embedding"`
+}
+
+func TestPipelineVectorSearch(t *testing.T) {
+	query := []float32{1, 0, 0}
+
+	t.Run("BSON", func(t *testing.T) {
+		pipeline := Pipeline().VectorSearch("embeddings", "embedding", query, 100, 2).Build()
+		assertPipeline(t, pipeline, mongo.Pipeline{
+			{{Key: "$vectorSearch", Value: bson.D{
+				{Key: "index", Value: "embeddings"},
+				{Key: "path", Value: "embedding"},
+				{Key: "queryVector", Value: bson.A{1.0, 0.0, 0.0}},
+				{Key: "numCandidates", Value: 100},
+				{Key: "limit", Value: 2},
+			}}},
+		})
+	})
+
+	t.Run("nearest neighbors", func(t *testing.T) {
+		// ATLAS_VECTOR_INDEX names a 3-dimension vector index on the
+		// embedding field of vector_docs, which only Atlas can serve.
+		index := os.Getenv("ATLAS_VECTOR_INDEX")
+		if index == "" {
+			t.Skip("requires an Atlas vector search index")
+		}
+		ctx := context.Background()
+		db := testDatabase(t)
+		_ = db.Collection("vector_docs").Drop(ctx)
+
+		model := New[testEmbedding, testEmbedding](db, "vector_docs")
+		for _, doc := range []testEmbedding{
+			{ID: "x", Embedding: []float32{1, 0, 0}},
+			{ID: "near-x", Embedding: []float32{0.9, 0.1, 0}},
+			{ID: "y", Embedding: []float32{0, 1, 0}},
+			{ID: "z", Embedding: []float32{0, 0, 1}},
+		} {
+			if err := model.Create(ctx, doc); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// The search index is updated asynchronously.
+		pipeline := Pipeline().VectorSearch(index, "embedding", query, 10, 2).Build()
+		var results []testEmbedding
+		for deadline := time.Now().Add(time.Minute); time.Now().Before(deadline); {
+			var err error
+			results, err = model.Aggregate(ctx, pipeline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) == 2 {
+				break
+			}
+			time.Sleep(time.Second)
+		}
+
+		if len(results) != 2 || results[0].ID != "x" || results[1].ID != "near-x" {
+			t.Fatalf("expected [x near-x], got %+v", results)
+		}
+	})
+}
+
+type testAgeBucket struct {
+	ID    any `bson:"_id"`
+	Count int `bson:"count"`
+}
+
+func TestPipelineBucket(t *testing.T) {
+	t.Run("BSON", func(t *testing.T) {
+		count := bson.D{{Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}
+		assertPipeline(t, Pipeline().Bucket("$age", []any{0, 18, 65}, "other", count).Build(), mongo.Pipeline{
+			{{Key: "$bucket", Value: bson.D{
+				{Key: "groupBy", Value: "$age"},
+				{Key: "boundaries", Value: bson.A{0, 18, 65}},
+				{Key: "default", Value: "other"},
+				{Key: "output", Value: count},
+			}}},
+		})
+
+		assertPipeline(t, Pipeline().Bucket("$age", []any{0, 18}, nil, nil).Build(), mongo.Pipeline{
+			{{Key: "$bucket", Value: bson.D{
+				{Key: "groupBy", Value: "$age"},
+				{Key: "boundaries", Value: bson.A{0, 18}},
+			}}},
+		})
+
+		assertPipeline(t, Pipeline().BucketAuto("$age", 4, nil).Build(), mongo.Pipeline{
+			{{Key: "$bucketAuto", Value: bson.D{
+				{Key: "groupBy", Value: "$age"},
+				{Key: "buckets", Value: 4},
+			}}},
+		})
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("bucket_users").Drop(ctx)
+
+	users := New[testUser, testAgeBucket](db, "bucket_users")
+	for i, age := range []int{12, 17, 25, 40, 64, 70, 80} {
+		if err := users.Create(ctx, testUser{ID: fmt.Sprint(i), Age: age}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("age ranges", func(t *testing.T) {
+		buckets, err := users.Aggregate(ctx, Pipeline().
+			Bucket("$age", []any{0, 18, 65}, "senior", nil).
+			Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got := make(map[string]int)
+		for _, b := range buckets {
+			got[fmt.Sprint(b.ID)] = b.Count
+		}
+		expected := map[string]int{"0": 2, "18": 3, "senior": 2}
+		if !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("auto", func(t *testing.T) {
+		buckets, err := users.Aggregate(ctx, Pipeline().BucketAuto("$age", 2, nil).Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		total := 0
+		for _, b := range buckets {
+			total += b.Count
+		}
+		if len(buckets) != 2 || total != 7 {
+			t.Fatalf("expected 7 users in 2 buckets, got %+v", buckets)
+		}
+	})
+}
+
+func TestPipelineReplaceRoot(t *testing.T) {
+	t.Run("BSON", func(t *testing.T) {
+		assertPipeline(t, Pipeline().ReplaceRoot("$address").ReplaceWith("$$ROOT").Build(), mongo.Pipeline{
+			{{Key: "$replaceRoot", Value: bson.D{{Key: "newRoot", Value: "$address"}}}},
+			{{Key: "$replaceWith", Value: "$$ROOT"}},
+		})
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("replace_customers").Drop(ctx)
+
+	customers := New[testCustomer, testAddress](db, "replace_customers")
+	for _, c := range []testCustomer{
+		{ID: "1", Name: "Alice", Address: testAddress{Street: "Rua A", City: "Lisbon", Zip: "1000"}},
+		{ID: "2", Name: "Bob", Address: testAddress{Street: "Rua B", City: "Porto", Zip: "4000"}},
+	} {
+		if err := customers.Create(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sortByID := bson.D{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}}
+	expected := []testAddress{
+		{Street: "Rua A", City: "Lisbon", Zip: "1000"},
+		{Street: "Rua B", City: "Porto", Zip: "4000"},
+	}
+
+	t.Run("ReplaceRoot", func(t *testing.T) {
+		addresses, err := customers.Aggregate(ctx, Pipeline().Stage(sortByID).ReplaceRoot("$address").Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(addresses, expected) {
+			t.Fatalf("expected %+v, got %+v", expected, addresses)
+		}
+	})
+
+	t.Run("ReplaceWith", func(t *testing.T) {
+		addresses, err := customers.Aggregate(ctx, Pipeline().Stage(sortByID).ReplaceWith("$address").Build())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(addresses, expected) {
+			t.Fatalf("expected %+v, got %+v", expected, addresses)
+		}
+	})
+}
+
+type testReading struct {
+	Time  time.Time `bson:"ts"`
+	Value float64   `bson:"value"`
+	Unit  string    `bson:"unit"`
+}
+
+func TestPipelineDensifyFill(t *testing.T) {
+	sortByTime := bson.D{{Key: "ts", Value: 1}}
+	fillOutput := bson.D{
+		{Key: "value", Value: bson.D{{Key: "method", Value: "linear"}}},
+		{Key: "unit", Value: bson.D{{Key: "value", Value: "celsius"}}},
+	}
+
+	t.Run("BSON", func(t *testing.T) {
+		assertPipeline(t, Pipeline().Densify("ts", "full", 1, "hour").Fill(nil, sortByTime, fillOutput).Build(), mongo.Pipeline{
+			{{Key: "$densify", Value: bson.D{
+				{Key: "field", Value: "ts"},
+				{Key: "range", Value: bson.D{
+					{Key: "step", Value: 1},
+					{Key: "bounds", Value: "full"},
+					{Key: "unit", Value: "hour"},
+				}},
+			}}},
+			{{Key: "$fill", Value: bson.D{
+				{Key: "sortBy", Value: sortByTime},
+				{Key: "output", Value: fillOutput},
+			}}},
+		})
+
+		assertPipeline(t, Pipeline().Densify("n", bson.A{0, 10}, 2, "").Build(), mongo.Pipeline{
+			{{Key: "$densify", Value: bson.D{
+				{Key: "field", Value: "n"},
+				{Key: "range", Value: bson.D{
+					{Key: "step", Value: 2},
+					{Key: "bounds", Value: bson.A{0, 10}},
+				}},
+			}}},
+		})
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	requireServerVersion(t, db, 5, 3)
+	_ = db.Collection("densify_readings").Drop(ctx)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	readings := New[testReading, testReading](db, "densify_readings")
+	for _, hour := range []int{0, 2, 4} {
+		reading := testReading{Time: start.Add(time.Duration(hour) * time.Hour), Value: float64(hour * 10), Unit: "celsius"}
+		if err := readings.Create(ctx, reading); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := readings.Aggregate(ctx, Pipeline().
+		Densify("ts", "full", 1, "hour").
+		Fill(nil, sortByTime, fillOutput).
+		Stage(bson.D{{Key: "$sort", Value: sortByTime}}).
+		Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 hourly readings, got %d", len(results))
+	}
+	for i, r := range results {
+		if !r.Time.Equal(start.Add(time.Duration(i)*time.Hour)) || r.Value != float64(i*10) || r.Unit != "celsius" {
+			t.Fatalf("unexpected reading %d: %+v", i, r)
+		}
+	}
+}
+
+type testInitials struct {
+	ID       string `bson:"_id"`
+	Initials string `bson:"initials"`
+}
+
+func TestPipelineFunction(t *testing.T) {
+	body := "function(first, last) { return first[0] + last[0] }"
+
+	t.Run("BSON", func(t *testing.T) {
+		expected := bson.D{{Key: "$function", Value: bson.D{
+			{Key: "body", Value: body},
+			{Key: "args", Value: []any{}},
+			{Key: "lang", Value: "js"},
+		}}}
+		if got := Function(body, nil, ""); !reflect.DeepEqual(got, expected) {
+			t.Fatalf("expected %v, got %v", expected, got)
+		}
+	})
+
+	t.Run("Stage", func(t *testing.T) {
+		assertPipeline(t, Pipeline().Function("initials", body, nil, "").Build(), mongo.Pipeline{
+			{{Key: "$addFields", Value: bson.D{{Key: "initials", Value: Function(body, nil, "")}}}},
+		})
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	requireServerVersion(t, db, 4, 4)
+	_ = db.Collection("function_employees").Drop(ctx)
+
+	employees := New[testEmployee, testInitials](db, "function_employees")
+	if err := employees.Create(ctx, testEmployee{ID: "1", FirstName: "Ada", LastName: "Lovelace"}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := employees.Aggregate(ctx, Pipeline().
+		Function("initials", body, []any{"$first_name", "$last_name"}, "js").
+		Build())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].Initials != "AL" {
+		t.Fatalf("expected initials AL, got %+v", results)
+	}
+}
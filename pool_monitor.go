@@ -0,0 +1,66 @@
+package mongodb
+
+import (
+	"log/slog"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+// LogPoolSaturation returns a pool monitor that logs a warning when
+// every connection of a pool is checked out, and when a checkout
+// times out waiting for a free connection.
+//
+// It is meant to be passed to WithPoolMonitor while debugging
+// "too many connections" issues. A nil logger uses slog.Default.
+func LogPoolSaturation(logger *slog.Logger) *event.PoolMonitor {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	var (
+		mu      sync.Mutex
+		maxSize = make(map[string]uint64)
+		inUse   = make(map[string]uint64)
+	)
+
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch e.Type {
+			case event.ConnectionPoolCreated:
+				if e.PoolOptions != nil {
+					maxSize[e.Address] = e.PoolOptions.MaxPoolSize
+				}
+			case event.ConnectionCheckedOut:
+				inUse[e.Address]++
+				if max := maxSize[e.Address]; max > 0 && inUse[e.Address] >= max {
+					logger.Warn(
+						"mongodb connection pool saturated",
+						"address", e.Address,
+						"in_use", inUse[e.Address],
+						"max_pool_size", max,
+					)
+				}
+			case event.ConnectionCheckedIn:
+				if inUse[e.Address] > 0 {
+					inUse[e.Address]--
+				}
+			case event.ConnectionCheckOutFailed:
+				if e.Reason == event.ReasonTimedOut {
+					logger.Warn(
+						"mongodb connection checkout timed out",
+						"address", e.Address,
+						"in_use", inUse[e.Address],
+						"duration", e.Duration,
+					)
+				}
+			case event.ConnectionPoolClosed:
+				delete(maxSize, e.Address)
+				delete(inUse, e.Address)
+			}
+		},
+	}
+}
@@ -0,0 +1,142 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// Query is a chainable find over a model's collection.
+//
+// Each builder method records a part of the query and returns the same
+// Query, so calls can be chained and terminated by One, All or Count:
+//
+//	users, err := model.Query().
+//		Filter(bson.D{{Key: "position", Value: "Dev"}}).
+//		Sort(bson.D{{Key: "age", Value: -1}}).
+//		Limit(10).
+//		All(ctx)
+type Query[T any] struct {
+	collection *mongo.Collection
+	config     *modelConfig
+	filter     any
+	sort       any
+	projection any
+	limit      *int64
+	skip       *int64
+}
+
+// Query starts a new chainable query over the collection.
+func (m *mongoModel[T, C]) Query() *Query[T] {
+	return &Query[T]{collection: m.collection, config: &m.config}
+}
+
+// Filter sets the query filter. An unset filter matches every document.
+func (q *Query[T]) Filter(filter any) *Query[T] {
+	q.filter = filter
+	return q
+}
+
+// Sort sets the sort order of the results.
+func (q *Query[T]) Sort(sort any) *Query[T] {
+	q.sort = sort
+	return q
+}
+
+// Project limits the fields returned for each document.
+func (q *Query[T]) Project(projection any) *Query[T] {
+	q.projection = projection
+	return q
+}
+
+// Limit sets the maximum number of documents to return.
+func (q *Query[T]) Limit(n int64) *Query[T] {
+	q.limit = &n
+	return q
+}
+
+// Skip sets the number of documents to skip before returning results.
+func (q *Query[T]) Skip(n int64) *Query[T] {
+	q.skip = &n
+	return q
+}
+
+// One returns the first document matched by the query.
+func (q *Query[T]) One(ctx context.Context) (T, error) {
+	defer q.config.track("Query.One")()
+
+	opts := options.FindOne()
+	if q.sort != nil {
+		opts = opts.SetSort(q.sort)
+	}
+	if q.projection != nil {
+		opts = opts.SetProjection(q.projection)
+	}
+	if q.skip != nil {
+		opts = opts.SetSkip(*q.skip)
+	}
+	if comment := q.config.queryComment("Query.One"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	var result T
+	if err := q.collection.FindOne(ctx, q.filterOrAll(), opts).Decode(&result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// All returns every document matched by the query.
+func (q *Query[T]) All(ctx context.Context) ([]T, error) {
+	defer q.config.track("Query.All")()
+
+	opts := options.Find()
+	if q.sort != nil {
+		opts = opts.SetSort(q.sort)
+	}
+	if q.projection != nil {
+		opts = opts.SetProjection(q.projection)
+	}
+	if q.limit != nil {
+		opts = opts.SetLimit(*q.limit)
+	}
+	if q.skip != nil {
+		opts = opts.SetSkip(*q.skip)
+	}
+	if comment := q.config.queryComment("Query.All"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	cursor, err := q.collection.Find(ctx, q.filterOrAll(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCursor[T](ctx, cursor)
+}
+
+// Count returns the number of documents matched by the query,
+// honoring Skip and Limit.
+func (q *Query[T]) Count(ctx context.Context) (int64, error) {
+	defer q.config.track("Query.Count")()
+
+	opts := options.Count()
+	if q.limit != nil {
+		opts = opts.SetLimit(*q.limit)
+	}
+	if q.skip != nil {
+		opts = opts.SetSkip(*q.skip)
+	}
+	if comment := q.config.queryComment("Query.Count"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	return q.collection.CountDocuments(ctx, q.filterOrAll(), opts)
+}
+
+func (q *Query[T]) filterOrAll() any {
+	if q.filter == nil {
+		return bson.D{}
+	}
+	return q.filter
+}
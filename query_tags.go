@@ -0,0 +1,60 @@
+package mongodb
+
+import (
+	"slices"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// WithQueryTags makes the model attach a structured comment to its
+// operations, holding tags plus the operation name under "op", e.g.
+// {"svc": "users", "op": "FindOne"}. The Atlas profiler, the slow query
+// log and $currentOp show the comment, so queries can be grouped by the
+// service and method that sent them.
+//
+// Every read and write a model method sends is tagged with that method's
+// name, including the reads of Query under "Query.One", "Query.All" and
+// "Query.Count", the counters of NextSequence and the locks of
+// AcquireLock; UpdateManyResult is tagged as UpdateMany. A method built on
+// another one, like FindManyExtJSON on FindMany, tags the calls with its
+// own name. Only index management and the administrative commands are
+// left untagged: EnsureUniqueIndex, CreateCollatedIndex, RequireIndexes,
+// ExportIndexes, ImportIndexes, IsCapped, IsCovered, Validate,
+// ReindexCollection and the hello command UpdateWithDiff and
+// ConvertIDsToObjectID send to check for transaction support.
+//
+// A comment set in the call's options is kept instead. Tags are ordered
+// by key. Comments on writes need MongoDB 4.4 or later.
+func WithQueryTags(tags map[string]string) ModelOption {
+	return func(c *modelConfig) {
+		c.queryTags = tags
+	}
+}
+
+// queryComment returns the tag comment for op, or nil when the model has
+// no query tags.
+func (m *mongoModel[T, C]) queryComment(op string) any {
+	return m.config.queryComment(op)
+}
+
+// queryComment returns the tag comment for op, or nil when no query tags
+// are configured. Query uses it to tag the reads it sends.
+func (c *modelConfig) queryComment(op string) any {
+	if len(c.queryTags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(c.queryTags))
+	for key := range c.queryTags {
+		if key != "op" {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	comment := make(bson.D, 0, len(keys)+1)
+	for _, key := range keys {
+		comment = append(comment, bson.E{Key: key, Value: c.queryTags[key]})
+	}
+	return append(comment, bson.E{Key: "op", Value: op})
+}
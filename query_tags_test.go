@@ -0,0 +1,89 @@
+package mongodb
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+)
+
+func TestQueryComment(t *testing.T) {
+	untagged := &mongoModel[testUser, testUser]{}
+	if comment := untagged.queryComment("FindOne"); comment != nil {
+		t.Fatalf("expected no comment, got %v", comment)
+	}
+
+	m := &mongoModel[testUser, testUser]{config: modelConfig{
+		queryTags: map[string]string{"svc": "users", "env": "prod"},
+	}}
+	expected := bson.D{{Key: "env", Value: "prod"}, {Key: "svc", Value: "users"}, {Key: "op", Value: "FindOne"}}
+	if comment := m.queryComment("FindOne"); !reflect.DeepEqual(comment, expected) {
+		t.Fatalf("expected %v, got %v", expected, comment)
+	}
+}
+
+func TestWithQueryTags(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		comments = make(map[string]bson.Raw)
+	)
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if doc, ok := e.Command.Lookup("comment").DocumentOK(); ok {
+				mu.Lock()
+				comments[doc.Lookup("op").StringValue()] = doc
+				mu.Unlock()
+			}
+		},
+	}
+	db := testDatabase(t, withCommandMonitor(monitor))
+	requireServerVersion(t, db, 4, 4)
+	_ = db.Collection("tagged_queries").Drop(ctx)
+
+	model := New[testUser, testUser](db, "tagged_queries", WithQueryTags(map[string]string{"svc": "users"}))
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: "1"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.FindLatest(ctx, nil, "age"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.SetNested(ctx, bson.D{{Key: "_id", Value: "1"}}, "position", "Dev"); err != nil {
+		t.Fatal(err)
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "position", Value: "Lead"}}}}
+	if _, err := model.Claim(ctx, bson.D{{Key: "position", Value: "Dev"}}, update); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Scan(ctx, 0, func(testUser) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.FindManyExtJSON(ctx, `{"_id": "1"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.Query().Filter(bson.D{{Key: "_id", Value: "1"}}).All(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.Query().Count(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, op := range []string{"Create", "FindOne", "FindLatest", "SetNested", "Claim", "Scan", "FindManyExtJSON", "Query.All", "Query.Count"} {
+		comment, ok := comments[op]
+		if !ok {
+			t.Fatalf("expected a comment for %s", op)
+		}
+		if comment.Lookup("svc").StringValue() != "users" {
+			t.Fatalf("expected svc users on %s, got %s", op, comment)
+		}
+	}
+}
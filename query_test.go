@@ -0,0 +1,82 @@
+package mongodb
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestQuery(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("query_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "query_users")
+	seed := []testUser{
+		{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30, Position: "Dev"},
+		{ID: "2", Name: "Bob", Email: "bob@test.com", Age: 35, Position: "Dev"},
+		{ID: "3", Name: "Carol", Email: "carol@test.com", Age: 40, Position: "Dev"},
+		{ID: "4", Name: "Dave", Email: "dave@test.com", Age: 45, Position: "QA"},
+	}
+	for _, u := range seed {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	devs := bson.D{{Key: "position", Value: "Dev"}}
+
+	t.Run("All", func(t *testing.T) {
+		users, err := model.Query().
+			Filter(devs).
+			Sort(bson.D{{Key: "age", Value: -1}}).
+			Project(bson.D{{Key: "name", Value: 1}, {Key: "age", Value: 1}}).
+			Skip(1).
+			Limit(1).
+			All(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 1 {
+			t.Fatalf("expected 1 user, got %d", len(users))
+		}
+		if users[0].Name != "Bob" {
+			t.Fatalf("expected Bob, got %s", users[0].Name)
+		}
+		if users[0].Email != "" {
+			t.Fatalf("expected email to be projected out, got %s", users[0].Email)
+		}
+	})
+
+	t.Run("One", func(t *testing.T) {
+		user, err := model.Query().
+			Filter(devs).
+			Sort(bson.D{{Key: "age", Value: 1}}).
+			One(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Alice" {
+			t.Fatalf("expected Alice, got %s", user.Name)
+		}
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		count, err := model.Query().Filter(devs).Limit(2).Count(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 2 {
+			t.Fatalf("expected 2, got %d", count)
+		}
+
+		count, err = model.Query().Count(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != 4 {
+			t.Fatalf("expected 4, got %d", count)
+		}
+	})
+}
@@ -0,0 +1,102 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ServedRead describes a read command and the server that served it.
+type ServedRead struct {
+	// CommandName is the read command, e.g. "find" or "aggregate".
+	CommandName string
+
+	// Address is the host:port of the server that served the read.
+	Address string
+
+	// Secondary reports whether the server was a replica set secondary
+	// when the read completed. Reads falling back to the primary, or
+	// served by a standalone or mongos, report false.
+	Secondary bool
+
+	// Duration is how long the read took.
+	Duration time.Duration
+}
+
+// readCommands are the commands reported to a served-read hook.
+var readCommands = map[string]bool{
+	"find":      true,
+	"getMore":   true,
+	"aggregate": true,
+	"count":     true,
+	"distinct":  true,
+}
+
+// servedReadMonitors returns a command monitor and a server monitor that
+// call hook for every successful read, chained after the monitors
+// already set on opts.
+//
+// The server monitor tracks the role of each member from the driver's
+// topology updates, so the command monitor can tell secondaries apart.
+func servedReadMonitors(
+	opts *options.ClientOptions,
+	hook func(ServedRead),
+) (*event.CommandMonitor, *event.ServerMonitor) {
+	var (
+		mu    sync.RWMutex
+		kinds = make(map[string]string)
+	)
+
+	commands := &event.CommandMonitor{}
+	if prev := opts.Monitor; prev != nil {
+		*commands = *prev
+	}
+	prevSucceeded := commands.Succeeded
+	commands.Succeeded = func(ctx context.Context, e *event.CommandSucceededEvent) {
+		if prevSucceeded != nil {
+			prevSucceeded(ctx, e)
+		}
+		if !readCommands[e.CommandName] {
+			return
+		}
+		address := connectionAddress(e.ConnectionID)
+		mu.RLock()
+		kind := kinds[address]
+		mu.RUnlock()
+		hook(ServedRead{
+			CommandName: e.CommandName,
+			Address:     address,
+			Secondary:   kind == "RSSecondary",
+			Duration:    e.Duration,
+		})
+	}
+
+	servers := &event.ServerMonitor{}
+	if prev := opts.ServerMonitor; prev != nil {
+		*servers = *prev
+	}
+	prevChanged := servers.ServerDescriptionChanged
+	servers.ServerDescriptionChanged = func(e *event.ServerDescriptionChangedEvent) {
+		if prevChanged != nil {
+			prevChanged(e)
+		}
+		mu.Lock()
+		kinds[e.Address.String()] = e.NewDescription.Kind
+		mu.Unlock()
+	}
+
+	return commands, servers
+}
+
+// connectionAddress strips the connection counter the driver appends to
+// the server address in connection IDs, e.g. "db1:27017[-12]".
+func connectionAddress(connectionID string) string {
+	if i := strings.LastIndex(connectionID, "[-"); i >= 0 {
+		return connectionID[:i]
+	}
+	return connectionID
+}
@@ -0,0 +1,117 @@
+package mongodb
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+func TestConnectionAddress(t *testing.T) {
+	for id, expected := range map[string]string{
+		"db1.example.com:27017[-12]": "db1.example.com:27017",
+		"localhost:27017":            "localhost:27017",
+	} {
+		if got := connectionAddress(id); got != expected {
+			t.Fatalf("expected %s, got %s", expected, got)
+		}
+	}
+}
+
+func TestServedReadMonitorsChain(t *testing.T) {
+	var started bool
+	prev := &event.CommandMonitor{
+		Started: func(context.Context, *event.CommandStartedEvent) { started = true },
+	}
+	c := NewConnector(
+		"db",
+		"mongodb://localhost:27017",
+		func(c *DatabaseConnector) {
+			c.ClientOptions = options.Client().ApplyURI(c.URI).SetMonitor(prev)
+		},
+		WithServedReadHook(func(ServedRead) {}),
+	).(*DatabaseConnector)
+
+	opts := c.BuildClientOptions()
+	if opts.Monitor == prev || opts.Monitor.Succeeded == nil || opts.ServerMonitor == nil {
+		t.Fatal("expected served-read monitors on client options")
+	}
+	opts.Monitor.Started(context.Background(), &event.CommandStartedEvent{})
+	if !started {
+		t.Fatal("expected existing command monitor to be kept")
+	}
+}
+
+func TestServedReadMonitorsIdempotent(t *testing.T) {
+	var reads int
+	c := NewConnector(
+		"db",
+		"mongodb://localhost:27017",
+		func(c *DatabaseConnector) {
+			c.ClientOptions = options.Client().ApplyURI(c.URI)
+		},
+		WithServedReadHook(func(ServedRead) { reads++ }),
+	).(*DatabaseConnector)
+
+	c.BuildClientOptions()
+	opts := c.BuildClientOptions()
+	if c.ClientOptions.Monitor != nil || c.ClientOptions.ServerMonitor != nil {
+		t.Fatal("expected the caller's client options to be left unchanged")
+	}
+
+	opts.Monitor.Succeeded(context.Background(), &event.CommandSucceededEvent{
+		CommandFinishedEvent: event.CommandFinishedEvent{CommandName: "find"},
+	})
+	if reads != 1 {
+		t.Fatalf("expected the hook to run once per read, got %d", reads)
+	}
+}
+
+func TestServedReadHook(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu    sync.Mutex
+		reads []ServedRead
+	)
+	db := testDatabase(
+		t,
+		func(c *DatabaseConnector) {
+			c.ClientOptions = options.Client().ApplyURI(c.URI).SetReadPreference(readpref.Secondary())
+		},
+		WithServedReadHook(func(r ServedRead) {
+			mu.Lock()
+			defer mu.Unlock()
+			reads = append(reads, r)
+		}),
+	)
+	requireReplicaSet(t, db)
+
+	var hello struct {
+		Hosts []string `bson:"hosts"`
+	}
+	if err := db.RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		t.Fatal(err)
+	}
+	if len(hello.Hosts) < 2 {
+		t.Skip("requires a replica set with a secondary")
+	}
+
+	model := New[testUser, testUser](db, "served_reads")
+	if _, err := model.FindMany(ctx, bson.D{}); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reads) == 0 {
+		t.Fatal("expected a served read")
+	}
+	if r := reads[len(reads)-1]; r.CommandName != "find" || !r.Secondary || r.Address == "" {
+		t.Fatalf("expected find served by a secondary, got %+v", r)
+	}
+}
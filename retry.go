@@ -0,0 +1,100 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// readRetryBackoff is the delay before the first read retry; each
+// following retry waits one more multiple of it.
+const readRetryBackoff = 50 * time.Millisecond
+
+// retryableReadCodes are the server error codes the driver treats as
+// retryable for reads: the node is unreachable, stepping down or
+// shutting down, so another attempt may reach a healthy one.
+var retryableReadCodes = []int{
+	6,     // HostUnreachable
+	7,     // HostNotFound
+	89,    // NetworkTimeout
+	91,    // ShutdownInProgress
+	134,   // ReadConcernMajorityNotAvailableYet
+	189,   // PrimarySteppedDown
+	262,   // ExceededTimeLimit
+	9001,  // SocketException
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// RetryableErrorFunc decides whether a failed read is worth retrying.
+type RetryableErrorFunc func(err error) bool
+
+// DefaultRetryableError is the RetryableErrorFunc used unless the model
+// is created with WithRetryableErrorFunc. It follows the driver's
+// retryable read classification: network errors and the server errors
+// reporting an unreachable, stepping down or shutting down node.
+func DefaultRetryableError(err error) bool {
+	if mongo.IsNetworkError(err) {
+		return true
+	}
+	var serverErr mongo.ServerError
+	if !errors.As(err, &serverErr) {
+		return false
+	}
+	for _, code := range retryableReadCodes {
+		if serverErr.HasErrorCode(code) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithReadRetries makes FindOne, FindMany, Count and EstimatedCount retry
+// a failed read up to attempts more times when the error is retryable,
+// waiting a little longer before each retry.
+//
+// The driver already retries a read once on its own; this is for
+// deployments where failovers outlast that single retry. Errors are
+// classified with DefaultRetryableError unless WithRetryableErrorFunc is
+// also given.
+func WithReadRetries(attempts int) ModelOption {
+	return func(c *modelConfig) {
+		c.readRetries = attempts
+	}
+}
+
+// WithRetryableErrorFunc replaces DefaultRetryableError as the policy
+// deciding which read errors WithReadRetries retries, e.g. to also retry
+// a custom server error, or to wrap DefaultRetryableError and exclude
+// one. It has no effect without WithReadRetries.
+func WithRetryableErrorFunc(fn RetryableErrorFunc) ModelOption {
+	return func(c *modelConfig) {
+		c.retryableError = fn
+	}
+}
+
+// retryRead calls read, retrying it while it fails with a retryable error
+// and retries are left. It gives up early when ctx ends, returning the
+// last error.
+func (m *mongoModel[T, C]) retryRead(ctx context.Context, read func() error) error {
+	retryable := m.config.retryableError
+	if retryable == nil {
+		retryable = DefaultRetryableError
+	}
+
+	err := read()
+	for attempt := 1; err != nil && attempt <= m.config.readRetries && retryable(err); attempt++ {
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(time.Duration(attempt) * readRetryBackoff):
+		}
+		err = read()
+	}
+	return err
+}
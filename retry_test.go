@@ -0,0 +1,85 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+func TestDefaultRetryableError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network", mongo.CommandError{Labels: []string{"NetworkError"}}, true},
+		{"stepped down", mongo.CommandError{Code: 189}, true},
+		{"duplicate key", mongo.CommandError{Code: 11000}, false},
+		{"not found", mongo.ErrNoDocuments, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := DefaultRetryableError(c.err); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestRetryRead(t *testing.T) {
+	errCustom := errors.New("custom transient error")
+
+	// read fails with errCustom until it has been called succeedOn times.
+	read := func(calls *int, succeedOn int) func() error {
+		return func() error {
+			*calls++
+			if *calls < succeedOn {
+				return errCustom
+			}
+			return nil
+		}
+	}
+
+	t.Run("default policy", func(t *testing.T) {
+		m := &mongoModel[testUser, testUser]{config: modelConfig{readRetries: 3}}
+		calls := 0
+		if err := m.retryRead(context.Background(), read(&calls, 3)); !errors.Is(err, errCustom) {
+			t.Fatalf("expected errCustom, got %v", err)
+		}
+		if calls != 1 {
+			t.Fatalf("expected 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("custom policy", func(t *testing.T) {
+		m := &mongoModel[testUser, testUser]{config: modelConfig{
+			readRetries: 3,
+			retryableError: func(err error) bool {
+				return errors.Is(err, errCustom) || DefaultRetryableError(err)
+			},
+		}}
+		calls := 0
+		if err := m.retryRead(context.Background(), read(&calls, 3)); err != nil {
+			t.Fatal(err)
+		}
+		if calls != 3 {
+			t.Fatalf("expected 3 calls, got %d", calls)
+		}
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		m := &mongoModel[testUser, testUser]{config: modelConfig{
+			readRetries:    1,
+			retryableError: func(error) bool { return true },
+		}}
+		calls := 0
+		if err := m.retryRead(context.Background(), read(&calls, 5)); !errors.Is(err, errCustom) {
+			t.Fatalf("expected errCustom, got %v", err)
+		}
+		if calls != 2 {
+			t.Fatalf("expected 2 calls, got %d", calls)
+		}
+	})
+}
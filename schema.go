@@ -0,0 +1,373 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+// SchemaError reports a field that does not satisfy a JSON schema.
+type SchemaError struct {
+	// Field is the dotted path of the field, or empty for the document.
+	Field string
+
+	// Reason describes the violated constraint.
+	Reason string
+}
+
+func (e *SchemaError) Error() string {
+	if e.Field == "" {
+		return "document " + e.Reason
+	}
+	return fmt.Sprintf("field %s %s", e.Field, e.Reason)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaViolation
+}
+
+// bsonTypeAliases maps the bsonType names of $jsonSchema to BSON types.
+var bsonTypeAliases = map[string][]bson.Type{
+	"double":              {bson.TypeDouble},
+	"string":              {bson.TypeString},
+	"object":              {bson.TypeEmbeddedDocument},
+	"array":               {bson.TypeArray},
+	"binData":             {bson.TypeBinary},
+	"undefined":           {bson.TypeUndefined},
+	"objectId":            {bson.TypeObjectID},
+	"bool":                {bson.TypeBoolean},
+	"date":                {bson.TypeDateTime},
+	"null":                {bson.TypeNull},
+	"regex":               {bson.TypeRegex},
+	"dbPointer":           {bson.TypeDBPointer},
+	"javascript":          {bson.TypeJavaScript},
+	"symbol":              {bson.TypeSymbol},
+	"javascriptWithScope": {bson.TypeCodeWithScope},
+	"int":                 {bson.TypeInt32},
+	"timestamp":           {bson.TypeTimestamp},
+	"long":                {bson.TypeInt64},
+	"decimal":             {bson.TypeDecimal128},
+	"minKey":              {bson.TypeMinKey},
+	"maxKey":              {bson.TypeMaxKey},
+	"number":              {bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128},
+}
+
+// jsonTypeAliases maps the JSON type names of $jsonSchema to BSON types.
+var jsonTypeAliases = map[string][]bson.Type{
+	"object":  {bson.TypeEmbeddedDocument},
+	"array":   {bson.TypeArray},
+	"number":  {bson.TypeInt32, bson.TypeInt64, bson.TypeDouble, bson.TypeDecimal128},
+	"boolean": {bson.TypeBoolean},
+	"string":  {bson.TypeString},
+	"null":    {bson.TypeNull},
+}
+
+// ValidateAgainstSchema checks doc against a MongoDB $jsonSchema on the
+// client, catching invalid documents before the round-trip to a server
+// validator. The schema may be given bare or wrapped in a $jsonSchema
+// key, as in a collection validator.
+//
+// Every violation is reported as a *SchemaError naming the field, and
+// the violations are returned joined; errors.Is(err, ErrSchemaViolation)
+// reports whether doc is invalid. The supported keywords are bsonType,
+// type, required, properties, additionalProperties, enum, minimum,
+// maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength,
+// pattern, items, minItems, maxItems, title and description; any other
+// keyword fails with an error instead of being silently ignored.
+func (m *mongoModel[T, C]) ValidateAgainstSchema(doc T, schema bson.M) error {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	rawSchema, err := bson.Marshal(schema)
+	if err != nil {
+		return err
+	}
+	if wrapped, ok := bson.Raw(rawSchema).Lookup("$jsonSchema").DocumentOK(); ok {
+		rawSchema = wrapped
+	}
+
+	var violations []error
+	value := bson.RawValue{Type: bson.TypeEmbeddedDocument, Value: raw}
+	if err := validateSchema("", value, rawSchema, &violations); err != nil {
+		return err
+	}
+	return errors.Join(violations...)
+}
+
+// validateSchema appends a *SchemaError to violations for every
+// constraint of schema that the value at path does not satisfy. It
+// returns an error only when the schema itself is invalid.
+func validateSchema(path string, value bson.RawValue, schema bson.Raw, violations *[]error) error {
+	elems, err := schema.Elements()
+	if err != nil {
+		return err
+	}
+
+	violate := func(field, format string, args ...any) {
+		*violations = append(*violations, &SchemaError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	for _, elem := range elems {
+		keyword, arg := elem.Key(), elem.Value()
+		switch keyword {
+		case "bsonType", "type":
+			aliases := bsonTypeAliases
+			if keyword == "type" {
+				aliases = jsonTypeAliases
+			}
+			names, err := schemaStrings(keyword, arg)
+			if err != nil {
+				return err
+			}
+			ok, err := matchesType(value.Type, names, aliases)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				violate(path, "must be of type %v, got %s", names, value.Type)
+				// The remaining keywords assume the expected type.
+				return nil
+			}
+
+		case "required":
+			names, err := schemaStrings(keyword, arg)
+			if err != nil {
+				return err
+			}
+			if doc, ok := value.DocumentOK(); ok {
+				for _, name := range names {
+					if _, err := doc.LookupErr(name); err != nil {
+						violate(joinPath(path, name), "is required")
+					}
+				}
+			}
+
+		case "properties":
+			properties, ok := arg.DocumentOK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be a document", keyword)
+			}
+			doc, ok := value.DocumentOK()
+			if !ok {
+				continue
+			}
+			props, err := properties.Elements()
+			if err != nil {
+				return err
+			}
+			for _, prop := range props {
+				field, err := doc.LookupErr(prop.Key())
+				if err != nil {
+					continue
+				}
+				propSchema, ok := prop.Value().DocumentOK()
+				if !ok {
+					return fmt.Errorf("schema of property %q must be a document", prop.Key())
+				}
+				if err := validateSchema(joinPath(path, prop.Key()), field, propSchema, violations); err != nil {
+					return err
+				}
+			}
+
+		case "additionalProperties":
+			doc, ok := value.DocumentOK()
+			if !ok {
+				continue
+			}
+			properties, _ := schema.Lookup("properties").DocumentOK()
+			extraSchema, isSchema := arg.DocumentOK()
+			allowed, isBool := arg.BooleanOK()
+			if !isSchema && !isBool {
+				return fmt.Errorf("schema keyword %q must be a boolean or a document", keyword)
+			}
+			fields, err := doc.Elements()
+			if err != nil {
+				return err
+			}
+			for _, field := range fields {
+				if _, err := properties.LookupErr(field.Key()); err == nil {
+					continue
+				}
+				fieldPath := joinPath(path, field.Key())
+				if isSchema {
+					if err := validateSchema(fieldPath, field.Value(), extraSchema, violations); err != nil {
+						return err
+					}
+				} else if !allowed {
+					violate(fieldPath, "is not allowed")
+				}
+			}
+
+		case "enum":
+			options, ok := arg.ArrayOK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be an array", keyword)
+			}
+			values, err := options.Values()
+			if err != nil {
+				return err
+			}
+			found := false
+			for _, option := range values {
+				if option.Equal(value) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				violate(path, "must be one of the enum values")
+			}
+
+		case "minimum", "maximum":
+			bound, ok := arg.AsFloat64OK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be a number", keyword)
+			}
+			n, ok := value.AsFloat64OK()
+			if !ok {
+				continue
+			}
+			limit := strconv.FormatFloat(bound, 'g', -1, 64)
+			if keyword == "minimum" {
+				exclusive, _ := schema.Lookup("exclusiveMinimum").BooleanOK()
+				if exclusive && n <= bound {
+					violate(path, "must be greater than %s", limit)
+				} else if n < bound {
+					violate(path, "must be at least %s", limit)
+				}
+			} else {
+				exclusive, _ := schema.Lookup("exclusiveMaximum").BooleanOK()
+				if exclusive && n >= bound {
+					violate(path, "must be less than %s", limit)
+				} else if n > bound {
+					violate(path, "must be at most %s", limit)
+				}
+			}
+
+		case "minLength", "maxLength":
+			bound, ok := arg.AsInt64OK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be an integer", keyword)
+			}
+			if s, ok := value.StringValueOK(); ok {
+				checkLength(path, keyword == "minLength", int64(utf8.RuneCountInString(s)), bound, "characters", violate)
+			}
+
+		case "minItems", "maxItems":
+			bound, ok := arg.AsInt64OK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be an integer", keyword)
+			}
+			if arr, ok := value.ArrayOK(); ok {
+				items, err := arr.Values()
+				if err != nil {
+					return err
+				}
+				checkLength(path, keyword == "minItems", int64(len(items)), bound, "items", violate)
+			}
+
+		case "pattern":
+			pattern, ok := arg.StringValueOK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be a string", keyword)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return fmt.Errorf("schema keyword %q: %w", keyword, err)
+			}
+			if s, ok := value.StringValueOK(); ok && !re.MatchString(s) {
+				violate(path, "must match pattern %q", pattern)
+			}
+
+		case "items":
+			itemSchema, ok := arg.DocumentOK()
+			if !ok {
+				return fmt.Errorf("schema keyword %q must be a document", keyword)
+			}
+			arr, ok := value.ArrayOK()
+			if !ok {
+				continue
+			}
+			items, err := arr.Values()
+			if err != nil {
+				return err
+			}
+			for i, item := range items {
+				if err := validateSchema(joinPath(path, strconv.Itoa(i)), item, itemSchema, violations); err != nil {
+					return err
+				}
+			}
+
+		case "exclusiveMinimum", "exclusiveMaximum", "title", "description":
+			// Modifiers and annotations, read alongside other keywords.
+
+		default:
+			return fmt.Errorf("unsupported schema keyword %q", keyword)
+		}
+	}
+	return nil
+}
+
+// checkLength reports a length below a minimum bound, or above a maximum one.
+func checkLength(path string, min bool, length, bound int64, unit string, violate func(string, string, ...any)) {
+	if min && length < bound {
+		violate(path, "must have at least %d %s", bound, unit)
+	}
+	if !min && length > bound {
+		violate(path, "must have at most %d %s", bound, unit)
+	}
+}
+
+// schemaStrings reads a keyword argument that is a string or an array of
+// strings.
+func schemaStrings(keyword string, arg bson.RawValue) ([]string, error) {
+	if s, ok := arg.StringValueOK(); ok {
+		return []string{s}, nil
+	}
+	arr, ok := arg.ArrayOK()
+	if !ok {
+		return nil, fmt.Errorf("schema keyword %q must be a string or an array of strings", keyword)
+	}
+	values, err := arr.Values()
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for _, v := range values {
+		s, ok := v.StringValueOK()
+		if !ok {
+			return nil, fmt.Errorf("schema keyword %q must be a string or an array of strings", keyword)
+		}
+		names = append(names, s)
+	}
+	return names, nil
+}
+
+// matchesType reports whether t is one of the named types.
+func matchesType(t bson.Type, names []string, aliases map[string][]bson.Type) (bool, error) {
+	for _, name := range names {
+		types, ok := aliases[name]
+		if !ok {
+			return false, fmt.Errorf("unknown schema type %q", name)
+		}
+		for _, candidate := range types {
+			if candidate == t {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// joinPath appends field to the dotted path.
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
@@ -0,0 +1,78 @@
+package mongodb
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+)
+
+func TestValidateAgainstSchema(t *testing.T) {
+	model := &mongoModel[testUser, testUser]{}
+	schema := bson.M{
+		"bsonType": "object",
+		"required": bson.A{"name", "age"},
+		"properties": bson.M{
+			"name":  bson.M{"bsonType": "string", "minLength": 1},
+			"age":   bson.M{"bsonType": "int", "minimum": 18},
+			"email": bson.M{"bsonType": "string", "pattern": "^[^@]+@[^@]+$"},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		user := testUser{Name: "Alice", Email: "alice@test.com", Age: 30}
+		if err := model.ValidateAgainstSchema(user, schema); err != nil {
+			t.Fatalf("expected valid document, got %v", err)
+		}
+	})
+
+	t.Run("underage", func(t *testing.T) {
+		err := model.ValidateAgainstSchema(testUser{Name: "Bob", Email: "bob@test.com", Age: 17}, schema)
+		if !errors.Is(err, ErrSchemaViolation) {
+			t.Fatalf("expected ErrSchemaViolation, got %v", err)
+		}
+		var schemaErr *SchemaError
+		if !errors.As(err, &schemaErr) {
+			t.Fatalf("expected *SchemaError, got %T", err)
+		}
+		if schemaErr.Field != "age" || schemaErr.Reason != "must be at least 18" {
+			t.Fatalf("expected age to be at least 18, got %q %q", schemaErr.Field, schemaErr.Reason)
+		}
+	})
+
+	t.Run("multiple violations", func(t *testing.T) {
+		err := model.ValidateAgainstSchema(testUser{Email: "invalid", Age: 1}, schema)
+		for _, expected := range []string{
+			"field name must have at least 1 characters",
+			"field age must be at least 18",
+			"field email must match pattern",
+		} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Fatalf("expected %q in %v", expected, err)
+			}
+		}
+	})
+
+	t.Run("required and wrapped schema", func(t *testing.T) {
+		wrapped := bson.M{"$jsonSchema": bson.M{
+			"required":             bson.A{"nickname"},
+			"additionalProperties": false,
+			"properties":           bson.M{"nickname": bson.M{"type": "string"}},
+		}}
+		// Map keys are marshaled in random order, so the violations are too.
+		err := model.ValidateAgainstSchema(testUser{Name: "Alice"}, wrapped)
+		for _, expected := range []string{"field nickname is required", "field name is not allowed"} {
+			if err == nil || !strings.Contains(err.Error(), expected) {
+				t.Fatalf("expected %q in %v", expected, err)
+			}
+		}
+	})
+
+	t.Run("unsupported keyword", func(t *testing.T) {
+		err := model.ValidateAgainstSchema(testUser{}, bson.M{"oneOf": bson.A{}})
+		if err == nil || errors.Is(err, ErrSchemaViolation) {
+			t.Fatalf("expected schema error, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// countersCollection is the collection holding the sequences of NextSequence.
+const countersCollection = "counters"
+
+// NextSequence atomically increments the named sequence and returns its
+// new value, starting at 1, e.g. for human-friendly sequential IDs.
+//
+// Sequences live in a dedicated "counters" collection of the model's
+// database, one document per name, so concurrent callers always receive
+// distinct, contiguous values.
+func (m *mongoModel[T, C]) NextSequence(ctx context.Context, name string) (int64, error) {
+	defer m.track("NextSequence")()
+
+	counters := m.collection.Database().Collection(countersCollection)
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+	if comment := m.queryComment("NextSequence"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	update := bson.D{
+		{Key: "$inc", Value: bson.D{{Key: "seq", Value: int64(1)}}},
+		{Key: "$setOnInsert", Value: bson.D{{Key: "created_at", Value: time.Now()}}},
+	}
+
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := counters.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: name}}, update, opts).Decode(&counter)
+	if mongo.IsDuplicateKeyError(err) {
+		// Two callers raced to create the sequence; the counter exists
+		// now, so the retry is a plain increment.
+		err = counters.FindOneAndUpdate(ctx, bson.D{{Key: "_id", Value: name}}, update, opts).Decode(&counter)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return counter.Seq, nil
+}
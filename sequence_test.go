@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestNextSequence(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection(countersCollection).Drop(ctx)
+
+	model := New[testUser, testUser](db, "sequence_users")
+
+	const n = 50
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		values []int64
+	)
+	for range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := model.NextSequence(ctx, "invoice")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			values = append(values, v)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if len(values) != n {
+		t.Fatalf("expected %d values, got %d", n, len(values))
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	for i, v := range values {
+		if v != int64(i+1) {
+			t.Fatalf("expected contiguous values 1..%d, got %v", n, values)
+		}
+	}
+
+	v, err := model.NextSequence(ctx, "order")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != 1 {
+		t.Fatalf("expected independent sequence to start at 1, got %d", v)
+	}
+}
@@ -0,0 +1,87 @@
+package mongodb
+
+import (
+	"sync"
+	"time"
+)
+
+// OperationStat holds the statistics recorded for one model operation.
+type OperationStat struct {
+	// Count is the number of calls, successful or not.
+	Count int64
+
+	// Latency is the cumulative time spent in the calls.
+	Latency time.Duration
+}
+
+// OperationStats maps a model method name, e.g. "FindOne", to its
+// recorded statistics.
+type OperationStats map[string]OperationStat
+
+// statsRecorder accumulates OperationStats safely across goroutines.
+type statsRecorder struct {
+	mu    sync.Mutex
+	stats OperationStats
+}
+
+// WithStats makes the model record the call count and cumulative latency
+// of every model method that talks to the server, each under its method
+// name, and of the One, All and Count calls of Query under "Query.One",
+// "Query.All" and "Query.Count". UpdateManyResult is recorded as
+// UpdateMany. Query and Stats themselves, and ValidateAgainstSchema,
+// which only checks a document locally, are not recorded. Read the
+// statistics with Stats. Calls made by other model methods, e.g.
+// FindManyExtJSON calling FindMany, are recorded as well. FindManyChannel
+// and WatchChannel are timed until their channels close.
+//
+// It is meant for profiling and asserting query behavior in tests
+// without wiring external metrics.
+func WithStats() ModelOption {
+	return func(c *modelConfig) {
+		c.stats = &statsRecorder{stats: make(OperationStats)}
+	}
+}
+
+// Stats returns a snapshot of the statistics recorded since the model was
+// created. It is empty unless the model was created with WithStats.
+func (m *mongoModel[T, C]) Stats() OperationStats {
+	snapshot := make(OperationStats)
+	if m.config.stats == nil {
+		return snapshot
+	}
+
+	m.config.stats.mu.Lock()
+	defer m.config.stats.mu.Unlock()
+	for op, stat := range m.config.stats.stats {
+		snapshot[op] = stat
+	}
+	return snapshot
+}
+
+// track starts timing a call to op and returns the function recording
+// it, meant to be deferred:
+//
+//	defer m.track("FindOne")()
+func (m *mongoModel[T, C]) track(op string) func() {
+	return m.config.track(op)
+}
+
+// track starts timing a call to op when stats are enabled. Query uses it
+// to record its terminal methods.
+func (c *modelConfig) track(op string) func() {
+	recorder := c.stats
+	if recorder == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		recorder.mu.Lock()
+		defer recorder.mu.Unlock()
+		stat := recorder.stats[op]
+		stat.Count++
+		stat.Latency += elapsed
+		recorder.stats[op] = stat
+	}
+}
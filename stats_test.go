@@ -0,0 +1,83 @@
+package mongodb
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		model := &mongoModel[testUser, testUser]{}
+		defer model.track("FindOne")()
+		if stats := model.Stats(); len(stats) != 0 {
+			t.Fatalf("expected no stats, got %v", stats)
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("stats_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "stats_users", WithStats())
+	for _, u := range []testUser{{ID: "1", Name: "Alice"}, {ID: "2", Name: "Bob"}} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 3 {
+		if _, err := model.FindOne(ctx, map[string]any{"_id": "1"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := model.FindOne(ctx, map[string]any{"_id": "missing"}); err == nil {
+		t.Fatal("expected ErrNotFound")
+	}
+	if _, err := model.FindMany(ctx, map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.SetNested(ctx, map[string]any{"_id": "2"}, "position", "QA"); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.Scan(ctx, 0, func(testUser) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.Query().All(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := model.ImportJSONL(ctx, strings.NewReader(`{"_id": "3", "name": "Carol"}`), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := model.DeleteMany(ctx, map[string]any{}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := model.Stats()
+	expected := map[string]int64{
+		"Create":      2,
+		"FindOne":     4,
+		"FindMany":    1,
+		"SetNested":   1,
+		"Scan":        1,
+		"Query.All":   1,
+		"ImportJSONL": 1,
+		"DeleteMany":  1,
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("expected %d operations, got %v", len(expected), stats)
+	}
+	for op, count := range expected {
+		if stats[op].Count != count {
+			t.Fatalf("expected %d %s calls, got %d", count, op, stats[op].Count)
+		}
+		if stats[op].Latency <= 0 {
+			t.Fatalf("expected %s latency, got %v", op, stats[op].Latency)
+		}
+	}
+
+	// Stats returns a snapshot.
+	stats["Create"] = OperationStat{}
+	if model.Stats()["Create"].Count != 2 {
+		t.Fatal("expected Stats to return a copy")
+	}
+}
@@ -0,0 +1,80 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readconcern"
+)
+
+// TransactionContext is the context WithTransaction passes to its
+// callback. It carries the transaction's session, so every model method
+// called with it, on any model of the same client, runs inside the
+// transaction.
+type TransactionContext struct {
+	context.Context
+
+	// Session is the session running the transaction.
+	Session *mongo.Session
+}
+
+// TransactionOption configures the transactions started by WithTransaction.
+type TransactionOption func(*options.TransactionOptionsBuilder)
+
+// WithSnapshotReadConcern makes every read in the transaction observe the
+// same point-in-time snapshot of the data, so documents read at different
+// moments are consistent with each other even while concurrent writers
+// change them.
+func WithSnapshotReadConcern() TransactionOption {
+	return func(opts *options.TransactionOptionsBuilder) {
+		opts.SetReadConcern(readconcern.Snapshot())
+	}
+}
+
+// WithTransaction runs fn in a transaction and commits it when fn returns
+// nil, or aborts it when fn fails. Writes of several models become atomic
+// by passing tx to their methods:
+//
+//	err := db.WithTransaction(ctx, func(tx TransactionContext) error {
+//		if err := users.Create(tx, user); err != nil {
+//			return err
+//		}
+//		return orders.Create(tx, order)
+//	})
+//
+// fn may run more than once, since transient transaction errors and
+// unknown commit results are retried, so it must not have side effects
+// outside the database. Transactions require a replica set or sharded
+// cluster. By default the transaction uses the client's read and write
+// concerns; opts such as WithSnapshotReadConcern override them.
+//
+// When ctx already carries a session, such as the tx of an outer
+// WithTransaction, fn runs once in that session instead of starting a
+// second transaction, so its writes commit or roll back with the outer
+// one, and opts are ignored.
+func (d *Database) WithTransaction(
+	ctx context.Context,
+	fn func(tx TransactionContext) error,
+	opts ...TransactionOption,
+) error {
+	if session := mongo.SessionFromContext(ctx); session != nil {
+		return fn(TransactionContext{Context: ctx, Session: session})
+	}
+
+	txOpts := options.Transaction()
+	for _, opt := range opts {
+		opt(txOpts)
+	}
+
+	session, err := d.Client().StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(ctx context.Context) (any, error) {
+		return nil, fn(TransactionContext{Context: ctx, Session: session})
+	}, txOpts)
+	return err
+}
@@ -0,0 +1,171 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+func TestWithTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	requireReplicaSet(t, db.Database)
+
+	// Collections cannot be created implicitly on every server version
+	// inside a transaction, so they are created up front.
+	for _, name := range []string{"tx_users", "tx_orders"} {
+		_ = db.Collection(name).Drop(ctx)
+		if err := db.CreateCollection(ctx, name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users := New[testUser, testUser](db.Database, "tx_users", WithSingleflight())
+	orders := New[testOrder, testOrder](db.Database, "tx_orders")
+
+	t.Run("rollback", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		err := db.WithTransaction(ctx, func(tx TransactionContext) error {
+			if err := users.Create(tx, testUser{ID: "1", Name: "Alice"}); err != nil {
+				return err
+			}
+			if err := orders.Create(tx, testOrder{ID: "order1"}); err != nil {
+				return err
+			}
+			// Reads in the transaction see its own writes.
+			if _, err := users.FindOne(tx, map[string]any{"_id": "1"}); err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected errAbort, got %v", err)
+		}
+
+		if _, err := users.FindOne(ctx, map[string]any{"_id": "1"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected user to be rolled back, got %v", err)
+		}
+		if _, err := orders.FindOne(ctx, map[string]any{"_id": "order1"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected order to be rolled back, got %v", err)
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		err := db.WithTransaction(ctx, func(tx TransactionContext) error {
+			if err := users.Create(tx, testUser{ID: "2", Name: "Bob"}); err != nil {
+				return err
+			}
+			return orders.Create(tx, testOrder{ID: "order2"})
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := users.FindOne(ctx, map[string]any{"_id": "2"}); err != nil {
+			t.Fatalf("expected committed user, got %v", err)
+		}
+		if _, err := orders.FindOne(ctx, map[string]any{"_id": "order2"}); err != nil {
+			t.Fatalf("expected committed order, got %v", err)
+		}
+	})
+
+	t.Run("nested", func(t *testing.T) {
+		if err := users.Create(ctx, testUser{ID: "3", Name: "Carol", Age: 30}); err != nil {
+			t.Fatal(err)
+		}
+
+		errAbort := errors.New("abort")
+		err := db.WithTransaction(ctx, func(tx TransactionContext) error {
+			_, _, err := users.UpdateWithDiff(
+				tx,
+				map[string]any{"_id": "3"},
+				map[string]any{"$set": map[string]any{"age": 31}},
+			)
+			if err != nil {
+				return err
+			}
+			err = db.WithTransaction(tx, func(tx TransactionContext) error {
+				return orders.Create(tx, testOrder{ID: "order3"})
+			})
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected errAbort, got %v", err)
+		}
+
+		user, err := users.FindOne(ctx, map[string]any{"_id": "3"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Age != 30 {
+			t.Fatalf("expected the update to be rolled back, got age %d", user.Age)
+		}
+		if _, err := orders.FindOne(ctx, map[string]any{"_id": "order3"}); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected nested order to be rolled back, got %v", err)
+		}
+	})
+}
+
+func TestWithSnapshotReadConcern(t *testing.T) {
+	t.Run("options", func(t *testing.T) {
+		opts := options.Transaction()
+		WithSnapshotReadConcern()(opts)
+
+		var txOpts options.TransactionOptions
+		for _, set := range opts.List() {
+			if err := set(&txOpts); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if txOpts.ReadConcern == nil || txOpts.ReadConcern.Level != "snapshot" {
+			t.Fatalf("expected snapshot read concern, got %+v", txOpts.ReadConcern)
+		}
+	})
+
+	ctx := context.Background()
+	db := NewDatabase(testDatabase(t))
+	requireReplicaSet(t, db.Database)
+	_ = db.Collection("snapshot_accounts").Drop(ctx)
+
+	accounts := New[testUser, testUser](db.Database, "snapshot_accounts")
+	for _, u := range []testUser{{ID: "a", Age: 10}, {ID: "b", Age: 10}} {
+		if err := accounts.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var first, second testUser
+	err := db.WithTransaction(ctx, func(tx TransactionContext) error {
+		var err error
+		if first, err = accounts.FindOne(tx, map[string]any{"_id": "a"}); err != nil {
+			return err
+		}
+
+		// A concurrent writer changes b after the snapshot was taken.
+		err = accounts.UpdateOne(ctx, map[string]any{"_id": "b"}, map[string]any{"$inc": map[string]any{"age": 5}})
+		if err != nil {
+			return err
+		}
+
+		second, err = accounts.FindOne(tx, map[string]any{"_id": "b"})
+		return err
+	}, WithSnapshotReadConcern())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first.Age != 10 || second.Age != 10 {
+		t.Fatalf("expected both reads from the snapshot, got %d and %d", first.Age, second.Age)
+	}
+	after, err := accounts.FindOne(ctx, map[string]any{"_id": "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Age != 15 {
+		t.Fatalf("expected the concurrent write to be applied, got %d", after.Age)
+	}
+}
@@ -0,0 +1,642 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// UpdateArrayElement updates the array elements selected by the
+// filtered positional operator ($[identifier]) in a single document.
+//
+// arrayFilters binds each identifier used in the update,
+// e.g. {"item.sku": "A1"} for "items.$[item].status".
+func (m *mongoModel[T, C]) UpdateArrayElement(
+	ctx context.Context,
+	filter any,
+	update any,
+	arrayFilters []any,
+) (*mongo.UpdateResult, error) {
+	defer m.track("UpdateArrayElement")()
+
+	opts := options.UpdateOne().SetArrayFilters(arrayFilters)
+	if comment := m.queryComment("UpdateArrayElement"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateOne(wctx, filter, update, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return nil, err
+	}
+	m.audit(ctx, "UpdateArrayElement", filter, update)
+	return result, err
+}
+
+// UpsertedIDs returns the _id of the documents upserted by a write, from
+// a *mongo.UpdateResult as returned by UpdateManyResult or a
+// *mongo.BulkWriteResult. Bulk results list the IDs in the order of the
+// upserting operations. It returns nil for other results and for writes
+// that upserted nothing.
+func UpsertedIDs(result any) []any {
+	switch result := result.(type) {
+	case *mongo.UpdateResult:
+		if result == nil || result.UpsertedID == nil {
+			return nil
+		}
+		return []any{result.UpsertedID}
+	case *mongo.BulkWriteResult:
+		if result == nil || len(result.UpsertedIDs) == 0 {
+			return nil
+		}
+		indexes := make([]int64, 0, len(result.UpsertedIDs))
+		for index := range result.UpsertedIDs {
+			indexes = append(indexes, index)
+		}
+		slices.Sort(indexes)
+		ids := make([]any, 0, len(indexes))
+		for _, index := range indexes {
+			ids = append(ids, result.UpsertedIDs[index])
+		}
+		return ids
+	}
+	return nil
+}
+
+// SetNested sets the embedded field addressed by a dotted path,
+// e.g. "address.city", in a single document.
+//
+// Only the addressed field is written; sibling fields of the embedded
+// document are preserved.
+func (m *mongoModel[T, C]) SetNested(
+	ctx context.Context,
+	filter any,
+	path string,
+	value any,
+) (*mongo.UpdateResult, error) {
+	defer m.track("SetNested")()
+
+	if err := validateFieldPath(path); err != nil {
+		return nil, err
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: path, Value: value}}}}
+	opts := options.UpdateOne()
+	if comment := m.queryComment("SetNested"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateOne(wctx, filter, update, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return nil, err
+	}
+	m.audit(ctx, "SetNested", filter, update)
+	return result, err
+}
+
+// RenameField renames a field in every document of the collection
+// using $rename, and returns the number of modified documents.
+//
+// Documents that do not have the old field are left untouched.
+func (m *mongoModel[T, C]) RenameField(ctx context.Context, oldName, newName string) (int64, error) {
+	defer m.track("RenameField")()
+
+	if err := validateFieldPath(oldName); err != nil {
+		return 0, err
+	}
+	if err := validateFieldPath(newName); err != nil {
+		return 0, err
+	}
+	if oldName == newName {
+		return 0, fmt.Errorf("%w: cannot rename %q to itself", ErrInvalidFieldPath, oldName)
+	}
+
+	filter := bson.D{{Key: oldName, Value: bson.D{{Key: "$exists", Value: true}}}}
+	update := bson.D{{Key: "$rename", Value: bson.D{{Key: oldName, Value: newName}}}}
+	opts := options.UpdateMany()
+	if comment := m.queryComment("RenameField"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateMany(wctx, filter, update, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return 0, err
+	}
+	m.audit(ctx, "RenameField", filter, update)
+	return result.ModifiedCount, err
+}
+
+// UpdateWithDiff applies update to the first document matching filter and
+// returns the document as it was before and after the update.
+//
+// The document is read first and then updated by its _id with
+// FindOneAndUpdate, so both versions always describe the same document.
+// On a replica set or sharded cluster both steps run in one transaction,
+// so before is exactly the state the update was applied to; on a
+// standalone server a concurrent writer may change the document in
+// between. When ctx carries a session, such as a TransactionContext, both
+// steps run in it instead. ErrNotFound is returned when nothing matches.
+func (m *mongoModel[T, C]) UpdateWithDiff(ctx context.Context, filter any, update any) (T, T, error) {
+	defer m.track("UpdateWithDiff")()
+
+	var before, after T
+	transactions, err := m.ownTransaction(ctx)
+	if err != nil {
+		return before, after, err
+	}
+
+	findOpts := options.FindOne()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if comment := m.queryComment("UpdateWithDiff"); comment != nil {
+		findOpts = findOpts.SetComment(comment)
+		opts = opts.SetComment(comment)
+	}
+	var byID bson.D
+	diff := func(ctx context.Context) error {
+		var zero T
+		before, after = zero, zero
+		raw, err := m.collection.FindOne(ctx, filter, findOpts).Raw()
+		if err != nil {
+			return err
+		}
+		if err := bson.Unmarshal(raw, &before); err != nil {
+			return err
+		}
+		byID = bson.D{{Key: "_id", Value: raw.Lookup("_id")}}
+		return m.collection.FindOneAndUpdate(ctx, byID, update, opts).Decode(&after)
+	}
+
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+	if transactions {
+		err = NewDatabase(m.collection.Database()).WithTransaction(wctx, func(tx TransactionContext) error {
+			return diff(tx)
+		})
+	} else {
+		err = diff(wctx)
+	}
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return before, after, err
+	}
+	m.audit(ctx, "UpdateWithDiff", byID, update)
+	return before, after, err
+}
+
+// UpsertReturning applies update to the document matching filter,
+// inserting it when nothing matches, and returns the resulting document.
+// This is the get-or-create-and-return primitive:
+//
+//	user, err := users.UpsertReturning(ctx,
+//		bson.D{{Key: "email", Value: "alice@test.com"}},
+//		bson.D{{Key: "$setOnInsert", Value: bson.D{{Key: "age", Value: 30}}}},
+//	)
+//
+// An inserted document is built from the equality fields of filter with
+// update applied on top.
+func (m *mongoModel[T, C]) UpsertReturning(ctx context.Context, filter any, update any) (T, error) {
+	defer m.track("UpsertReturning")()
+
+	opts := options.FindOneAndUpdate().
+		SetUpsert(true).
+		SetReturnDocument(options.After)
+	if comment := m.queryComment("UpsertReturning"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	var result T
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	err := m.collection.FindOneAndUpdate(wctx, filter, update, opts).Decode(&result)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return result, err
+	}
+	m.audit(ctx, "UpsertReturning", filter, update)
+	return result, err
+}
+
+// ApplyMergePatch applies an RFC 7386 JSON Merge Patch to the first
+// document matching filter and returns the updated document, or
+// ErrNotFound when nothing matches.
+//
+// The patch is translated into a single update: members set to null are
+// $unset, nested objects are merged field by field through dotted paths,
+// and every other value, arrays included, is $set as a whole. For example
+//
+//	{"name": "Alice", "address": {"city": "Lisbon", "zip": null}}
+//
+// sets name and address.city and unsets address.zip. The patch may use
+// Extended JSON for values such as dates. Members whose name contains a
+// dot or starts with "$" fail with ErrInvalidFieldPath. Unlike RFC 7386,
+// a nested object only merges into an existing embedded document, and an
+// empty object leaves the field unchanged.
+func (m *mongoModel[T, C]) ApplyMergePatch(ctx context.Context, filter any, patch []byte) (T, error) {
+	defer m.track("ApplyMergePatch")()
+
+	var result T
+
+	var doc bson.D
+	if err := bson.UnmarshalExtJSON(patch, false, &doc); err != nil {
+		return result, fmt.Errorf("invalid merge patch: %w", err)
+	}
+	set, unset := bson.D{}, bson.D{}
+	if err := mergePatchOps("", doc, &set, &unset); err != nil {
+		return result, err
+	}
+
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+	if len(update) == 0 {
+		// The server rejects an empty update.
+		opts := withDefault(nil, m.queryComment("ApplyMergePatch"), func(o *options.FindOneOptions) *any { return &o.Comment })
+		return m.FindOne(ctx, filter, opts...)
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if comment := m.queryComment("ApplyMergePatch"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	err := m.collection.FindOneAndUpdate(wctx, filter, update, opts).Decode(&result)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return result, err
+	}
+	m.audit(ctx, "ApplyMergePatch", filter, update)
+	return result, err
+}
+
+// mergePatchOps appends the $set and $unset operations of the merge patch
+// doc, whose members are relative to prefix.
+func mergePatchOps(prefix string, doc bson.D, set, unset *bson.D) error {
+	for _, e := range doc {
+		if strings.Contains(e.Key, ".") {
+			return fmt.Errorf("%w: merge patch member %q contains a dot", ErrInvalidFieldPath, e.Key)
+		}
+		path := joinPath(prefix, e.Key)
+		if err := validateFieldPath(path); err != nil {
+			return err
+		}
+
+		switch v := e.Value.(type) {
+		case nil:
+			*unset = append(*unset, bson.E{Key: path, Value: ""})
+		case bson.D:
+			if err := mergePatchOps(path, v, set, unset); err != nil {
+				return err
+			}
+		default:
+			*set = append(*set, bson.E{Key: path, Value: v})
+		}
+	}
+	return nil
+}
+
+// CompareAndSwap sets field to newValue on the document matching filter,
+// but only while field still holds expected, and reports whether the swap
+// happened. This makes state machine transitions atomic:
+//
+//	swapped, err := orders.CompareAndSwap(ctx,
+//		bson.D{{Key: "_id", Value: id}}, "status", "pending", "paid")
+//
+// A false result without error means no document matched, either because
+// field changed concurrently or because filter matches nothing. An
+// expected value of nil also matches a missing field.
+func (m *mongoModel[T, C]) CompareAndSwap(
+	ctx context.Context,
+	filter any,
+	field string,
+	expected, newValue any,
+) (bool, error) {
+	defer m.track("CompareAndSwap")()
+
+	if err := validateFieldPath(field); err != nil {
+		return false, err
+	}
+
+	guard := bson.D{{Key: field, Value: expected}}
+	casFilter := guard
+	if filter != nil {
+		casFilter = bson.D{{Key: "$and", Value: bson.A{filter, guard}}}
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: field, Value: newValue}}}}
+	opts := options.UpdateOne()
+	if comment := m.queryComment("CompareAndSwap"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateOne(wctx, casFilter, update, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return false, err
+	}
+	if result.MatchedCount == 0 {
+		return false, err
+	}
+	m.audit(ctx, "CompareAndSwap", casFilter, update)
+	return true, err
+}
+
+// AddToSet adds values to the array field of every document matching
+// filter with $addToSet, skipping the values a document already holds,
+// and returns the number of modified documents, e.g. to bulk-tag a
+// subset. A document without the field gets a new array.
+//
+// Each value is added as one element, so a slice value is added as a
+// nested array; pass its elements as separate values instead. No values
+// modifies nothing.
+func (m *mongoModel[T, C]) AddToSet(ctx context.Context, filter any, field string, values ...any) (int64, error) {
+	defer m.track("AddToSet")()
+
+	if err := validateFieldPath(field); err != nil {
+		return 0, err
+	}
+	if len(values) == 0 {
+		return 0, nil
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	var value any = bson.D{{Key: "$each", Value: values}}
+	if len(values) == 1 {
+		value = values[0]
+	}
+	update := bson.D{{Key: "$addToSet", Value: bson.D{{Key: field, Value: value}}}}
+	opts := options.UpdateMany()
+	if comment := m.queryComment("AddToSet"); comment != nil {
+		opts = opts.SetComment(comment)
+	}
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateMany(wctx, filter, update, opts)
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return 0, err
+	}
+	m.audit(ctx, "AddToSet", filter, update)
+	return result.ModifiedCount, err
+}
+
+// MutateVersionField is the field Mutate stores a document's version in
+// to detect concurrent modifications. It is not part of T; documents
+// that never went through Mutate start at version 0.
+const MutateVersionField = "_version"
+
+// mutateMaxAttempts is how many times Mutate tries a read-modify-write
+// before giving up with ErrConcurrentModification.
+const mutateMaxAttempts = 16
+
+// Mutate reads the first document matching filter, applies fn to it and
+// replaces the stored document with the result, returning the saved
+// document.
+//
+// The replace only succeeds if nobody else saved the document in the
+// meantime, as tracked by MutateVersionField. On a conflict the document
+// is read again and fn is applied afresh, so fn must not have side
+// effects; after too many conflicts ErrConcurrentModification is
+// returned. An error from fn aborts without writing, and ErrNotFound is
+// returned when nothing matches. Only writers using Mutate bump the
+// version, and the replace drops fields T does not declare.
+func (m *mongoModel[T, C]) Mutate(ctx context.Context, filter any, fn func(*T) error) (T, error) {
+	defer m.track("Mutate")()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+	findOpts := options.FindOne()
+	opts := options.Replace()
+	if comment := m.queryComment("Mutate"); comment != nil {
+		findOpts = findOpts.SetComment(comment)
+		opts = opts.SetComment(comment)
+	}
+
+	for range mutateMaxAttempts {
+		var doc T
+		current := m.collection.FindOne(ctx, filter, findOpts)
+		raw, err := current.Raw()
+		if err != nil {
+			return doc, err
+		}
+		if err := current.Decode(&doc); err != nil {
+			return doc, err
+		}
+
+		version, _ := raw.Lookup(MutateVersionField).AsInt64OK()
+		guard := bson.D{{Key: "_id", Value: raw.Lookup("_id")}}
+		if version == 0 {
+			guard = append(guard, bson.E{Key: MutateVersionField, Value: bson.D{{Key: "$in", Value: bson.A{nil, 0}}}})
+		} else {
+			guard = append(guard, bson.E{Key: MutateVersionField, Value: version})
+		}
+
+		if err := fn(&doc); err != nil {
+			var zero T
+			return zero, err
+		}
+		replacement, err := versionedReplacement(doc, version+1)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+
+		wctx, cancel := m.writeContext(ctx)
+		result, err := m.collection.ReplaceOne(wctx, guard, replacement, opts)
+		cancel()
+		if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+			var zero T
+			return zero, err
+		}
+		if result.MatchedCount > 0 {
+			m.audit(ctx, "Mutate", guard, replacement)
+			return doc, err
+		}
+	}
+
+	var zero T
+	return zero, ErrConcurrentModification
+}
+
+// versionedReplacement encodes doc with MutateVersionField set to version.
+func versionedReplacement(doc any, version int64) (bson.D, error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	elems, err := bson.Raw(raw).Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	replacement := make(bson.D, 0, len(elems)+1)
+	for _, elem := range elems {
+		if elem.Key() != MutateVersionField {
+			replacement = append(replacement, bson.E{Key: elem.Key(), Value: elem.Value()})
+		}
+	}
+	return append(replacement, bson.E{Key: MutateVersionField, Value: version}), nil
+}
+
+// PreviewUpdate returns the first limit documents matching filter, in _id
+// order, as they would look after update, without changing the stored
+// ones. A limit of zero or less previews every match.
+//
+// The update is applied inside a transaction that is always aborted, so
+// every update operator and update pipelines behave exactly as in
+// UpdateMany. Like any transaction, it requires a replica set or sharded
+// cluster, and concurrent writers to the previewed documents may hit
+// write conflicts until it is aborted. Since a transaction cannot be
+// partly rolled back, it fails with ErrNestedTransaction when ctx already
+// carries a session.
+func (m *mongoModel[T, C]) PreviewUpdate(ctx context.Context, filter any, update any, limit int) ([]T, error) {
+	defer m.track("PreviewUpdate")()
+
+	if mongo.SessionFromContext(ctx) != nil {
+		return nil, ErrNestedTransaction
+	}
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	session, err := m.collection.Database().Client().StartSession()
+	if err != nil {
+		return nil, err
+	}
+	defer session.EndSession(ctx)
+	if err := session.StartTransaction(); err != nil {
+		return nil, err
+	}
+	defer func() { _ = session.AbortTransaction(context.WithoutCancel(ctx)) }()
+	txCtx := mongo.NewSessionContext(ctx, session)
+
+	byID := bson.D{{Key: "_id", Value: 1}}
+	findOpts := options.Find().SetSort(byID).SetProjection(byID)
+	if limit > 0 {
+		findOpts = findOpts.SetLimit(int64(limit))
+	}
+	updateOpts := options.UpdateMany()
+	resultOpts := options.Find().SetSort(byID)
+	if comment := m.queryComment("PreviewUpdate"); comment != nil {
+		findOpts = findOpts.SetComment(comment)
+		updateOpts = updateOpts.SetComment(comment)
+		resultOpts = resultOpts.SetComment(comment)
+	}
+	cursor, err := m.collection.Find(txCtx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := decodeCursor[bson.Raw](txCtx, cursor)
+	if err != nil || len(matches) == 0 {
+		return make([]T, 0), err
+	}
+
+	ids := make(bson.A, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.Lookup("_id"))
+	}
+	previewed := bson.D{{Key: "_id", Value: bson.D{{Key: "$in", Value: ids}}}}
+	if _, err := m.collection.UpdateMany(txCtx, previewed, update, updateOpts); err != nil {
+		return nil, err
+	}
+
+	cursor, err = m.collection.Find(txCtx, previewed, resultOpts)
+	if err != nil {
+		return nil, err
+	}
+	return decodeCursor[T](txCtx, cursor)
+}
+
+// updatePipelineStages are the stages allowed in an update pipeline.
+var updatePipelineStages = map[string]bool{
+	"$addFields":   true,
+	"$set":         true,
+	"$project":     true,
+	"$unset":       true,
+	"$replaceRoot": true,
+	"$replaceWith": true,
+}
+
+// UpdateOneWithPipeline updates a single document using an aggregation
+// pipeline, so the new values can be computed from the document itself,
+// e.g. setting full_name from first_name and last_name:
+//
+//	mongo.Pipeline{{{Key: "$set", Value: bson.D{{Key: "full_name", Value: bson.D{
+//		{Key: "$concat", Value: bson.A{"$first_name", " ", "$last_name"}},
+//	}}}}}}
+//
+// The pipeline must not be empty and may only contain the stages the
+// server accepts for updates: $addFields, $set, $project, $unset,
+// $replaceRoot and $replaceWith. ErrInvalidPipeline is returned otherwise.
+func (m *mongoModel[T, C]) UpdateOneWithPipeline(
+	ctx context.Context,
+	filter any,
+	pipeline mongo.Pipeline,
+	opts ...*options.UpdateOneOptions,
+) (*mongo.UpdateResult, error) {
+	defer m.track("UpdateOneWithPipeline")()
+
+	if err := validateUpdatePipeline(pipeline); err != nil {
+		return nil, err
+	}
+
+	opts = withDefault(opts, m.queryComment("UpdateOneWithPipeline"), func(o *options.UpdateOneOptions) *any { return &o.Comment })
+	wctx, cancel := m.writeContext(ctx)
+	defer cancel()
+
+	result, err := m.collection.UpdateOne(wctx, filter, pipeline, BuildUpdateOneOptions(opts...))
+	if err = writeConcernTimeout(err); err != nil && !errors.Is(err, ErrWriteConcernTimeout) {
+		return nil, err
+	}
+	m.audit(ctx, "UpdateOneWithPipeline", filter, pipeline)
+	return result, err
+}
+
+// validateUpdatePipeline checks that pipeline is usable as an update.
+func validateUpdatePipeline(pipeline mongo.Pipeline) error {
+	if len(pipeline) == 0 {
+		return fmt.Errorf("%w: empty update pipeline", ErrInvalidPipeline)
+	}
+	for i, stage := range pipeline {
+		if len(stage) != 1 {
+			return fmt.Errorf("%w: stage %d must have exactly one operator", ErrInvalidPipeline, i)
+		}
+		if !updatePipelineStages[stage[0].Key] {
+			return fmt.Errorf("%w: stage %d: %s is not allowed in an update", ErrInvalidPipeline, i, stage[0].Key)
+		}
+	}
+	return nil
+}
+
+// validateFieldPath checks that path is a usable dotted field path.
+func validateFieldPath(path string) error {
+	if path == "" {
+		return fmt.Errorf("%w: empty path", ErrInvalidFieldPath)
+	}
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			return fmt.Errorf("%w: empty segment in %q", ErrInvalidFieldPath, path)
+		}
+		if strings.HasPrefix(segment, "$") {
+			return fmt.Errorf("%w: segment %q in %q starts with $", ErrInvalidFieldPath, segment, path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,682 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+type testAddress struct {
+	Street string `bson:"street"`
+	City   string `bson:"city"`
+	Zip    string `bson:"zip"`
+}
+
+type testCustomer struct {
+	ID      string      `bson:"_id,omitempty"`
+	Name    string      `bson:"name"`
+	Address testAddress `bson:"address"`
+}
+
+func TestUpdateArrayElement(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("array_orders").Drop(ctx)
+
+	model := New[testOrder, testOrder](db, "array_orders")
+	err := model.Create(ctx, testOrder{
+		ID: "order1",
+		Items: []struct {
+			SKU    string `bson:"sku"`
+			Qty    int    `bson:"qty"`
+			Status string `bson:"status"`
+		}{
+			{SKU: "A1", Qty: 1, Status: "pending"},
+			{SKU: "B1", Qty: 2, Status: "pending"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := model.UpdateArrayElement(
+		ctx,
+		map[string]any{"_id": "order1"},
+		map[string]any{"$set": map[string]any{"items.$[item].status": "shipped"}},
+		[]any{map[string]any{"item.sku": "B1"}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ModifiedCount != 1 {
+		t.Fatalf("expected 1 modified, got %d", result.ModifiedCount)
+	}
+
+	order, err := model.FindOne(ctx, map[string]any{"_id": "order1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range order.Items {
+		switch item.SKU {
+		case "A1":
+			if item.Status != "pending" {
+				t.Fatalf("unexpected change on A1: %s", item.Status)
+			}
+		case "B1":
+			if item.Status != "shipped" {
+				t.Fatalf("expected shipped, got %s", item.Status)
+			}
+		}
+	}
+}
+
+func TestUpdateManyResult(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("users_upserted").Drop(ctx)
+
+	model := New[testUser, testUser](db, "users_upserted")
+	upsert := true
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Position: "Dev"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := model.UpdateManyResult(
+		ctx,
+		bson.D{{Key: "name", Value: "Bob"}},
+		bson.D{{Key: "$set", Value: bson.D{{Key: "position", Value: "QA"}}}},
+		&options.UpdateManyOptions{Upsert: &upsert},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.UpsertedCount != 1 {
+		t.Fatalf("expected 1 upsert, got %d", result.UpsertedCount)
+	}
+
+	ids := UpsertedIDs(result)
+	if len(ids) != 1 {
+		t.Fatalf("expected 1 upserted id, got %v", ids)
+	}
+	user, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: ids[0]}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Bob" || user.Position != "QA" {
+		t.Fatalf("unexpected upserted user %+v", user)
+	}
+
+	t.Run("no upsert", func(t *testing.T) {
+		result, err := model.UpdateManyResult(
+			ctx,
+			bson.D{{Key: "name", Value: "Alice"}},
+			bson.D{{Key: "$set", Value: bson.D{{Key: "age", Value: 30}}}},
+			&options.UpdateManyOptions{Upsert: &upsert},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result.UpsertedCount != 0 || result.MatchedCount != 1 {
+			t.Fatalf("expected 1 match and no upsert, got %+v", result)
+		}
+		if ids := UpsertedIDs(result); ids != nil {
+			t.Fatalf("expected no upserted ids, got %v", ids)
+		}
+	})
+}
+
+func TestUpsertedIDs(t *testing.T) {
+	t.Run("bulk", func(t *testing.T) {
+		result := &mongo.BulkWriteResult{UpsertedIDs: map[int64]any{3: "c", 0: "a", 1: "b"}}
+		if got := UpsertedIDs(result); !reflect.DeepEqual(got, []any{"a", "b", "c"}) {
+			t.Fatalf("expected [a b c], got %v", got)
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		for _, result := range []any{nil, &mongo.UpdateResult{}, &mongo.BulkWriteResult{}, (*mongo.UpdateResult)(nil)} {
+			if got := UpsertedIDs(result); got != nil {
+				t.Fatalf("expected nil for %#v, got %v", result, got)
+			}
+		}
+	})
+}
+
+func TestSetNested(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("nested_customers").Drop(ctx)
+
+	model := New[testCustomer, testCustomer](db, "nested_customers")
+	err := model.Create(ctx, testCustomer{
+		ID:   "1",
+		Name: "Alice",
+		Address: testAddress{
+			Street: "Main St",
+			City:   "Lisbon",
+			Zip:    "1000",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := model.SetNested(ctx, map[string]any{"_id": "1"}, "address.city", "Porto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ModifiedCount != 1 {
+		t.Fatalf("expected 1 modified, got %d", result.ModifiedCount)
+	}
+
+	customer, err := model.FindOne(ctx, map[string]any{"_id": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if customer.Address.City != "Porto" {
+		t.Fatalf("expected Porto, got %s", customer.Address.City)
+	}
+	if customer.Address.Street != "Main St" || customer.Address.Zip != "1000" {
+		t.Fatalf("expected siblings preserved, got %+v", customer.Address)
+	}
+}
+
+func TestRenameField(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("rename_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "rename_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "Dev"},
+		{ID: "2", Name: "Bob", Position: "QA"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modified, err := model.RenameField(ctx, "position", "role")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified != 2 {
+		t.Fatalf("expected 2 modified, got %d", modified)
+	}
+
+	raw := New[map[string]any, map[string]any](db, "rename_users")
+	docs, err := raw.FindMany(ctx, map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, doc := range docs {
+		if _, ok := doc["position"]; ok {
+			t.Fatalf("expected position to be removed, got %v", doc)
+		}
+		if _, ok := doc["role"]; !ok {
+			t.Fatalf("expected role to be present, got %v", doc)
+		}
+	}
+
+	if _, err := model.RenameField(ctx, "role", "role"); !errors.Is(err, ErrInvalidFieldPath) {
+		t.Fatalf("expected ErrInvalidFieldPath, got %v", err)
+	}
+}
+
+func TestUpdateWithDiff(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("diff_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "diff_users")
+	original := testUser{ID: "1", Name: "Alice", Email: "alice@test.com", Age: 30, Position: "Dev"}
+	if err := model.Create(ctx, original); err != nil {
+		t.Fatal(err)
+	}
+
+	before, after, err := model.UpdateWithDiff(
+		ctx,
+		map[string]any{"email": "alice@test.com"},
+		map[string]any{"$set": map[string]any{"age": 31}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before != original {
+		t.Fatalf("expected before %+v, got %+v", original, before)
+	}
+
+	expected := original
+	expected.Age = 31
+	if after != expected {
+		t.Fatalf("expected after %+v, got %+v", expected, after)
+	}
+
+	_, _, err = model.UpdateWithDiff(
+		ctx,
+		map[string]any{"email": "missing@test.com"},
+		map[string]any{"$set": map[string]any{"age": 1}},
+	)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpsertReturning(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("upsert_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "upsert_users")
+
+	t.Run("insert", func(t *testing.T) {
+		user, err := model.UpsertReturning(
+			ctx,
+			map[string]any{"_id": "1", "email": "alice@test.com"},
+			map[string]any{"$set": map[string]any{"name": "Alice", "age": 30}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := testUser{ID: "1", Email: "alice@test.com", Name: "Alice", Age: 30}
+		if user != expected {
+			t.Fatalf("expected %+v, got %+v", expected, user)
+		}
+	})
+
+	t.Run("update", func(t *testing.T) {
+		user, err := model.UpsertReturning(
+			ctx,
+			map[string]any{"_id": "1", "email": "alice@test.com"},
+			map[string]any{"$inc": map[string]any{"age": 1}},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if user.Name != "Alice" || user.Age != 31 {
+			t.Fatalf("expected Alice aged 31, got %+v", user)
+		}
+
+		users, err := model.FindMany(ctx, map[string]any{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(users) != 1 {
+			t.Fatalf("expected 1 user, got %d", len(users))
+		}
+	})
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Run("invalid member", func(t *testing.T) {
+		model := &mongoModel[testCustomer, testCustomer]{}
+		for _, patch := range []string{`{"address.city": "Lisbon"}`, `{"$set": {"name": "x"}}`} {
+			_, err := model.ApplyMergePatch(context.Background(), nil, []byte(patch))
+			if !errors.Is(err, ErrInvalidFieldPath) {
+				t.Fatalf("expected ErrInvalidFieldPath for %s, got %v", patch, err)
+			}
+		}
+	})
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("patch_customers").Drop(ctx)
+
+	model := New[bson.M, bson.M](db, "patch_customers")
+	err := model.Create(ctx, bson.M{
+		"_id":     "1",
+		"name":    "Alice",
+		"email":   "alice@test.com",
+		"address": bson.M{"city": "Porto", "zip": "4000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := model.ApplyMergePatch(
+		ctx,
+		bson.M{"_id": "1"},
+		[]byte(`{"name": "Alice Smith", "email": null, "address": {"zip": null}}`),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patched["name"] != "Alice Smith" {
+		t.Fatalf("expected patched name, got %v", patched["name"])
+	}
+	if _, ok := patched["email"]; ok {
+		t.Fatalf("expected email to be unset, got %v", patched["email"])
+	}
+	address, _ := patched["address"].(bson.D)
+	if len(address) != 1 || address[0].Key != "city" || address[0].Value != "Porto" {
+		t.Fatalf("expected address {city: Porto}, got %v", patched["address"])
+	}
+
+	_, err = model.ApplyMergePatch(ctx, bson.M{"_id": "missing"}, []byte(`{"name": "x"}`))
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("cas_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "cas_users")
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice", Position: "pending"}); err != nil {
+		t.Fatal(err)
+	}
+	byID := map[string]any{"_id": "1"}
+
+	swapped, err := model.CompareAndSwap(ctx, byID, "position", "pending", "active")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Fatal("expected the swap to succeed")
+	}
+
+	swapped, err = model.CompareAndSwap(ctx, byID, "position", "pending", "archived")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Fatal("expected a stale swap to fail")
+	}
+
+	user, err := model.FindOne(ctx, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Position != "active" {
+		t.Fatalf("expected active, got %s", user.Position)
+	}
+
+	if _, err := model.CompareAndSwap(ctx, byID, "", "a", "b"); !errors.Is(err, ErrInvalidFieldPath) {
+		t.Fatalf("expected ErrInvalidFieldPath, got %v", err)
+	}
+}
+
+func TestAddToSet(t *testing.T) {
+	type taggedUser struct {
+		ID       string   `bson:"_id"`
+		Position string   `bson:"position"`
+		Tags     []string `bson:"tags"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("tagged_users").Drop(ctx)
+
+	model := New[taggedUser, taggedUser](db, "tagged_users")
+	for _, u := range []taggedUser{
+		{ID: "1", Position: "Dev", Tags: []string{"remote"}},
+		{ID: "2", Position: "Dev"},
+		{ID: "3", Position: "QA"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	devs := bson.D{{Key: "position", Value: "Dev"}}
+
+	modified, err := model.AddToSet(ctx, devs, "tags", "engineering", "remote")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified != 2 {
+		t.Fatalf("expected 2 modified, got %d", modified)
+	}
+
+	modified, err = model.AddToSet(ctx, devs, "tags", "engineering")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified != 0 {
+		t.Fatalf("expected a repeated call to modify nothing, got %d", modified)
+	}
+
+	users, err := model.FindMany(ctx, bson.D{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range users {
+		switch u.ID {
+		case "1", "2":
+			if len(u.Tags) != 2 {
+				t.Fatalf("expected 2 unique tags for %s, got %v", u.ID, u.Tags)
+			}
+		case "3":
+			if len(u.Tags) != 0 {
+				t.Fatalf("expected QA to stay untagged, got %v", u.Tags)
+			}
+		}
+	}
+}
+
+func TestMutate(t *testing.T) {
+	type counter struct {
+		ID    string `bson:"_id"`
+		Value int    `bson:"value"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("mutate_counters").Drop(ctx)
+
+	model := New[counter, counter](db, "mutate_counters")
+	if err := model.Create(ctx, counter{ID: "hits"}); err != nil {
+		t.Fatal(err)
+	}
+	byID := bson.D{{Key: "_id", Value: "hits"}}
+
+	const workers, increments = 4, 5
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*increments)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range increments {
+				_, err := model.Mutate(ctx, byID, func(c *counter) error {
+					c.Value++
+					return nil
+				})
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := model.FindOne(ctx, byID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Value != workers*increments {
+		t.Fatalf("expected %d, got %d", workers*increments, got.Value)
+	}
+
+	t.Run("aborted", func(t *testing.T) {
+		errAbort := errors.New("abort")
+		_, err := model.Mutate(ctx, byID, func(c *counter) error {
+			c.Value = 0
+			return errAbort
+		})
+		if !errors.Is(err, errAbort) {
+			t.Fatalf("expected errAbort, got %v", err)
+		}
+		if got, _ := model.FindOne(ctx, byID); got.Value != workers*increments {
+			t.Fatalf("expected the document unchanged, got %d", got.Value)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := model.Mutate(ctx, bson.D{{Key: "_id", Value: "missing"}}, func(*counter) error { return nil })
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	})
+}
+
+func TestVersionedReplacement(t *testing.T) {
+	doc := bson.D{{Key: "name", Value: "Alice"}, {Key: MutateVersionField, Value: int64(7)}}
+	replacement, err := versionedReplacement(doc, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replacement) != 2 || replacement[1].Key != MutateVersionField || replacement[1].Value != int64(3) {
+		t.Fatalf("expected the version replaced with 3, got %v", replacement)
+	}
+}
+
+func TestPreviewUpdate(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	requireReplicaSet(t, db)
+	_ = db.Collection("preview_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "preview_users")
+	for _, u := range []testUser{
+		{ID: "1", Name: "Alice", Position: "Dev"},
+		{ID: "2", Name: "Bob", Position: "Dev"},
+		{ID: "3", Name: "Carol", Position: "Dev"},
+		{ID: "4", Name: "Dave", Position: "QA"},
+	} {
+		if err := model.Create(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	devs := bson.D{{Key: "position", Value: "Dev"}}
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "position", Value: "Lead"}}}}
+
+	previews, err := model.PreviewUpdate(ctx, devs, update, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(previews) != 2 || previews[0].ID != "1" || previews[1].ID != "2" {
+		t.Fatalf("expected the first 2 devs, got %+v", previews)
+	}
+	for _, p := range previews {
+		if p.Position != "Lead" {
+			t.Fatalf("expected the preview to show Lead, got %+v", p)
+		}
+	}
+
+	stored, err := model.FindMany(ctx, devs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != 3 {
+		t.Fatalf("expected the 3 stored devs unchanged, got %+v", stored)
+	}
+
+	t.Run("no matches", func(t *testing.T) {
+		previews, err := model.PreviewUpdate(ctx, bson.D{{Key: "position", Value: "CEO"}}, update, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if previews == nil || len(previews) != 0 {
+			t.Fatalf("expected an empty preview, got %v", previews)
+		}
+	})
+
+	t.Run("in a transaction", func(t *testing.T) {
+		err := NewDatabase(db).WithTransaction(ctx, func(tx TransactionContext) error {
+			_, err := model.PreviewUpdate(tx, devs, update, 0)
+			return err
+		})
+		if !errors.Is(err, ErrNestedTransaction) {
+			t.Fatalf("expected ErrNestedTransaction, got %v", err)
+		}
+	})
+}
+
+func TestUpdateOneWithPipeline(t *testing.T) {
+	type person struct {
+		ID        string `bson:"_id"`
+		FirstName string `bson:"first_name"`
+		LastName  string `bson:"last_name"`
+		FullName  string `bson:"full_name,omitempty"`
+	}
+
+	ctx := context.Background()
+	db := testDatabase(t)
+	_ = db.Collection("pipeline_people").Drop(ctx)
+
+	model := New[person, person](db, "pipeline_people")
+	if err := model.Create(ctx, person{ID: "1", FirstName: "Ada", LastName: "Lovelace"}); err != nil {
+		t.Fatal(err)
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "full_name", Value: bson.D{
+			{Key: "$concat", Value: bson.A{"$first_name", " ", "$last_name"}},
+		}}}}},
+	}
+	result, err := model.UpdateOneWithPipeline(ctx, map[string]any{"_id": "1"}, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ModifiedCount != 1 {
+		t.Fatalf("expected 1 modified, got %d", result.ModifiedCount)
+	}
+
+	p, err := model.FindOne(ctx, map[string]any{"_id": "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.FullName != "Ada Lovelace" {
+		t.Fatalf("expected Ada Lovelace, got %q", p.FullName)
+	}
+}
+
+func TestValidateUpdatePipeline(t *testing.T) {
+	invalid := []mongo.Pipeline{
+		nil,
+		{{{Key: "$match", Value: bson.D{}}}},
+		{{{Key: "$set", Value: bson.D{}}, {Key: "$unset", Value: "a"}}},
+	}
+	for _, pipeline := range invalid {
+		if err := validateUpdatePipeline(pipeline); !errors.Is(err, ErrInvalidPipeline) {
+			t.Fatalf("expected ErrInvalidPipeline for %v, got %v", pipeline, err)
+		}
+	}
+
+	valid := mongo.Pipeline{
+		{{Key: "$set", Value: bson.D{{Key: "a", Value: 1}}}},
+		{{Key: "$unset", Value: "b"}},
+	}
+	if err := validateUpdatePipeline(valid); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateFieldPath(t *testing.T) {
+	valid := []string{"name", "address.city", "a.b.c"}
+	for _, path := range valid {
+		if err := validateFieldPath(path); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", path, err)
+		}
+	}
+
+	invalid := []string{"", ".city", "address.", "address..city", "$set", "address.$city"}
+	for _, path := range invalid {
+		if err := validateFieldPath(path); !errors.Is(err, ErrInvalidFieldPath) {
+			t.Fatalf("expected ErrInvalidFieldPath for %q, got %v", path, err)
+		}
+	}
+}
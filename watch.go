@@ -0,0 +1,91 @@
+package mongodb
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// ChangeEvent is a change stream event for a collection of T.
+type ChangeEvent[T any] struct {
+	// ID is the resume token of the event.
+	ID bson.Raw `bson:"_id"`
+
+	// OperationType is the kind of change, e.g. "insert", "update" or "delete".
+	OperationType string `bson:"operationType"`
+
+	// FullDocument is the document after the change. It is the zero
+	// value for deletes, and for updates of documents deleted since.
+	FullDocument T `bson:"fullDocument"`
+
+	// DocumentKey holds the _id (and shard key) of the changed document.
+	DocumentKey bson.Raw `bson:"documentKey"`
+
+	// ClusterTime is the time of the change in the oplog.
+	ClusterTime bson.Timestamp `bson:"clusterTime"`
+}
+
+// WatchChannel opens a change stream on the collection and delivers its
+// events on a channel, decoding the full document of each change into T.
+// Updates look up the current document, so FullDocument is populated.
+//
+// The stream runs in its own goroutine until ctx is cancelled or the
+// stream fails. Both channels are closed when it stops; a failure is
+// sent on the error channel first, while a cancelled ctx is a clean
+// shutdown and reports no error. Change streams require a replica set
+// or sharded cluster.
+func (m *mongoModel[T, C]) WatchChannel(
+	ctx context.Context,
+	pipeline mongo.Pipeline,
+) (<-chan ChangeEvent[T], <-chan error) {
+	events := make(chan ChangeEvent[T])
+	errs := make(chan error, 1)
+
+	if pipeline == nil {
+		pipeline = mongo.Pipeline{}
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+		defer m.track("WatchChannel")()
+
+		opts := options.ChangeStream().SetFullDocument(options.UpdateLookup)
+		if comment := m.queryComment("WatchChannel"); comment != nil {
+			opts = opts.SetComment(comment)
+		}
+		stream, err := m.collection.Watch(ctx, pipeline, opts)
+		if err != nil {
+			reportStreamError(ctx, errs, err)
+			return
+		}
+		defer stream.Close(context.WithoutCancel(ctx))
+
+		for stream.Next(ctx) {
+			var event ChangeEvent[T]
+			if err := stream.Decode(&event); err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+		reportStreamError(ctx, errs, stream.Err())
+	}()
+
+	return events, errs
+}
+
+// reportStreamError sends err unless it is nil or ctx has ended, in which
+// case the error is the stream noticing the shutdown.
+func reportStreamError(ctx context.Context, errs chan<- error, err error) {
+	if err == nil || ctx.Err() != nil {
+		return
+	}
+	errs <- err
+}
@@ -0,0 +1,68 @@
+package mongodb
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestWatchChannel(t *testing.T) {
+	db := testDatabase(t)
+	requireReplicaSet(t, db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = db.Collection("watch_users").Drop(ctx)
+	if err := db.CreateCollection(ctx, "watch_users"); err != nil {
+		t.Fatal(err)
+	}
+
+	model := New[testUser, testUser](db, "watch_users")
+	events, errs := model.WatchChannel(ctx, nil)
+
+	// The stream is opened asynchronously; keep inserting until the
+	// first event arrives.
+	timeout := time.After(10 * time.Second)
+	for i := 0; ; i++ {
+		if err := model.Create(ctx, testUser{ID: fmt.Sprintf("%d", i), Name: "Alice"}); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case event := <-events:
+			if event.OperationType != "insert" {
+				t.Fatalf("expected insert, got %s", event.OperationType)
+			}
+			if event.FullDocument.Name != "Alice" {
+				t.Fatalf("unexpected document %+v", event.FullDocument)
+			}
+		case err := <-errs:
+			t.Fatal(err)
+		case <-timeout:
+			t.Fatal("timed out waiting for change event")
+		case <-time.After(200 * time.Millisecond):
+			continue
+		}
+		break
+	}
+
+	cancel()
+
+	deadline := time.After(5 * time.Second)
+	for events != nil || errs != nil {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+		case err, ok := <-errs:
+			if !ok {
+				errs = nil
+				continue
+			}
+			t.Fatalf("unexpected error on shutdown: %v", err)
+		case <-deadline:
+			t.Fatal("channels were not closed after cancel")
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/mongo"
+)
+
+// writeConcernFailedCode is the server error code of a write concern
+// that timed out waiting for replication.
+const writeConcernFailedCode = 64
+
+// WithWriteTimeout bounds each write of the model by d, so a lagging
+// replica set cannot block writes waiting for a majority write concern
+// indefinitely. It applies to the core writes, such as Create, UpdateOne
+// and DeleteMany, and to the write helpers, such as SetNested, Claim and
+// Mutate. The write concern itself is left as configured on the client or
+// database.
+//
+// The driver no longer sends a wtimeout, so d is applied as the timeout
+// of the whole operation, which the server also enforces on the wait for
+// replication. When that wait expires the write has been applied on the
+// primary but may not be durable yet; it is audited like any other write
+// and the method returns an error matching ErrWriteConcernTimeout, which
+// callers may treat as a warning rather than a failure.
+func WithWriteTimeout(d time.Duration) ModelOption {
+	return func(c *modelConfig) {
+		c.writeTimeout = d
+	}
+}
+
+// writeContext returns ctx bounded by the configured write timeout.
+func (m *mongoModel[T, C]) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if m.config.writeTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, m.config.writeTimeout)
+}
+
+// writeConcernTimeout wraps err with ErrWriteConcernTimeout when it
+// reports a write that was applied but timed out waiting for its write
+// concern, and returns any other err unchanged.
+func writeConcernTimeout(err error) error {
+	var writeErr mongo.WriteException
+	if !errors.As(err, &writeErr) || len(writeErr.WriteErrors) > 0 || writeErr.WriteConcernError == nil {
+		return err
+	}
+	wce := writeErr.WriteConcernError
+	if wce.Code == writeConcernFailedCode || wce.IsMaxTimeMSExpiredError() {
+		return fmt.Errorf("%w: %w", ErrWriteConcernTimeout, err)
+	}
+	return err
+}
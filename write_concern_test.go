@@ -0,0 +1,121 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/writeconcern"
+)
+
+func TestWriteConcernTimeout(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"wtimeout", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 64}}, true},
+		{"max time", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 50}}, true},
+		{"other write concern", mongo.WriteException{WriteConcernError: &mongo.WriteConcernError{Code: 100}}, false},
+		{"write error", mongo.WriteException{
+			WriteErrors:       mongo.WriteErrors{{Code: 11000}},
+			WriteConcernError: &mongo.WriteConcernError{Code: 64},
+		}, false},
+		{"command error", mongo.CommandError{Code: 50}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := errors.Is(writeConcernTimeout(c.err), ErrWriteConcernTimeout); got != c.want {
+				t.Fatalf("expected %v, got %v", c.want, got)
+			}
+		})
+	}
+}
+
+func TestWithWriteTimeout(t *testing.T) {
+	ctx := context.Background()
+	db := testDatabase(t)
+	requireReplicaSet(t, db)
+	_ = db.Collection("wtimeout_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "wtimeout_users", WithWriteTimeout(time.Second))
+	failWriteConcern(t, db, "insert")
+	err := model.Create(ctx, testUser{ID: "1", Name: "Alice"})
+	if !errors.Is(err, ErrWriteConcernTimeout) {
+		t.Fatalf("expected ErrWriteConcernTimeout, got %v", err)
+	}
+
+	if _, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: "1"}}); err != nil {
+		t.Fatalf("expected the write to be applied, got %v", err)
+	}
+
+	t.Run("helper", func(t *testing.T) {
+		failWriteConcern(t, db, "update")
+		_, err := model.SetNested(ctx, bson.D{{Key: "_id", Value: "1"}}, "name", "Alicia")
+		if !errors.Is(err, ErrWriteConcernTimeout) {
+			t.Fatalf("expected ErrWriteConcernTimeout, got %v", err)
+		}
+		user, err := model.FindOne(ctx, bson.D{{Key: "_id", Value: "1"}})
+		if err != nil || user.Name != "Alicia" {
+			t.Fatalf("expected the update to be applied, got %+v, %v", user, err)
+		}
+	})
+}
+
+func TestWithWriteTimeoutKeepsWriteConcern(t *testing.T) {
+	ctx := context.Background()
+
+	var w bson.RawValue
+	monitor := &event.CommandMonitor{
+		Started: func(_ context.Context, e *event.CommandStartedEvent) {
+			if e.CommandName == "insert" {
+				w = e.Command.Lookup("writeConcern", "w")
+			}
+		},
+	}
+	db := testDatabase(t, withCommandMonitor(monitor))
+	db = db.Client().Database(db.Name(), options.Database().SetWriteConcern(writeconcern.W1()))
+	_ = db.Collection("wtimeout_concern_users").Drop(ctx)
+
+	model := New[testUser, testUser](db, "wtimeout_concern_users", WithWriteTimeout(time.Second))
+	if err := model.Create(ctx, testUser{ID: "1", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if n, ok := w.AsInt64OK(); !ok || n != 1 {
+		t.Fatalf("expected the inherited w: 1, got %v", w)
+	}
+}
+
+// failWriteConcern simulates replication lag by making the next command
+// report a timed out write concern after being applied.
+func failWriteConcern(t *testing.T, db *mongo.Database, command string) {
+	t.Helper()
+	ctx := context.Background()
+	admin := db.Client().Database("admin")
+	err := admin.RunCommand(ctx, bson.D{
+		{Key: "configureFailPoint", Value: "failCommand"},
+		{Key: "mode", Value: bson.D{{Key: "times", Value: 1}}},
+		{Key: "data", Value: bson.D{
+			{Key: "failCommands", Value: bson.A{command}},
+			{Key: "writeConcernError", Value: bson.D{
+				{Key: "code", Value: 64},
+				{Key: "errmsg", Value: "waiting for replication timed out"},
+				{Key: "errInfo", Value: bson.D{{Key: "wtimeout", Value: true}}},
+			}},
+		}},
+	}).Err()
+	if err != nil {
+		t.Skipf("failCommand fail point unavailable: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = admin.RunCommand(context.Background(), bson.D{
+			{Key: "configureFailPoint", Value: "failCommand"},
+			{Key: "mode", Value: "off"},
+		}).Err()
+	})
+}